@@ -0,0 +1,104 @@
+package album_sync
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/jsonl"
+)
+
+// State is the local database of uploaded files, used to skip them in following syncs.
+// Implementations must be safe for concurrent use.
+//
+// A state should be used for one directory and album pair.
+type State interface {
+	// IsUploaded returns true if the file has been uploaded, and it has not changed since then.
+	IsUploaded(path string, info fs.FileInfo) (bool, error)
+
+	// MarkUploaded records that the file has been uploaded as the given media item.
+	MarkUploaded(path string, info fs.FileInfo, mediaItemID string) error
+}
+
+// entry is the state of an uploaded file.
+type entry struct {
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"modTime"`
+	MediaItemID string    `json:"mediaItemId"`
+}
+
+func (e entry) matches(info fs.FileInfo) bool {
+	return e.Size == info.Size() && e.ModTime.Equal(info.ModTime())
+}
+
+// MemoryState is a State kept in memory. It's lost when the program ends.
+type MemoryState struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewMemoryState returns an empty in-memory state.
+func NewMemoryState() *MemoryState {
+	return &MemoryState{entries: make(map[string]entry)}
+}
+
+// IsUploaded implements State.
+func (s *MemoryState) IsUploaded(path string, info fs.FileInfo) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[path]
+	return ok && e.matches(info), nil
+}
+
+// MarkUploaded implements State.
+func (s *MemoryState) MarkUploaded(path string, info fs.FileInfo, mediaItemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[path] = entry{Path: path, Size: info.Size(), ModTime: info.ModTime(), MediaItemID: mediaItemID}
+	return nil
+}
+
+// FileState is a State persisted in a file. Every uploaded file is appended to
+// the file as a JSON line, so an interrupted sync keeps the files uploaded so far.
+type FileState struct {
+	mem *MemoryState
+	log *jsonl.Log[entry]
+}
+
+// OpenFileState opens, or creates, the state stored in the given file.
+// The caller must close it.
+func OpenFileState(name string) (*FileState, error) {
+	mem := NewMemoryState()
+	log, err := jsonl.Open(name, func(e entry) {
+		mem.entries[e.Path] = e
+	}, func() []entry {
+		return slices.Collect(maps.Values(mem.entries))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening state: %w", err)
+	}
+	return &FileState{mem: mem, log: log}, nil
+}
+
+// IsUploaded implements State.
+func (s *FileState) IsUploaded(path string, info fs.FileInfo) (bool, error) {
+	return s.mem.IsUploaded(path, info)
+}
+
+// MarkUploaded implements State.
+func (s *FileState) MarkUploaded(path string, info fs.FileInfo, mediaItemID string) error {
+	e := entry{Path: path, Size: info.Size(), ModTime: info.ModTime(), MediaItemID: mediaItemID}
+	if err := s.log.Append(e); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return s.mem.MarkUploaded(path, info, mediaItemID)
+}
+
+// Close closes the state file.
+func (s *FileState) Close() error {
+	return s.log.Close()
+}
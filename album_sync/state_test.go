@@ -0,0 +1,79 @@
+package album_sync_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/album_sync"
+)
+
+func TestFileState(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "a.jpg", "b.jpg")
+	name := filepath.Join(dir, ".state.jsonl")
+	infoA := fileInfo(t, filepath.Join(dir, "a.jpg"))
+	infoB := fileInfo(t, filepath.Join(dir, "b.jpg"))
+
+	state, err := album_sync.OpenFileState(name)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	if err := state.MarkUploaded("a.jpg", infoA, "fooId-a"); err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	if err := state.Close(); err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	// A crash could leave a partial line.
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	_, _ = f.WriteString(`{"path":"b.j`)
+	_ = f.Close()
+
+	state, err = album_sync.OpenFileState(name)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	defer state.Close()
+
+	assertUploaded(t, state, "a.jpg", infoA, true)
+	assertUploaded(t, state, "b.jpg", infoB, false)
+
+	if err := state.MarkUploaded("b.jpg", infoB, "fooId-b"); err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	assertUploaded(t, state, "b.jpg", infoB, true)
+
+	t.Run("Should not match modified files", func(t *testing.T) {
+		later := time.Now().Add(time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, "a.jpg"), later, later); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		assertUploaded(t, state, "a.jpg", fileInfo(t, filepath.Join(dir, "a.jpg")), false)
+	})
+}
+
+func assertUploaded(t *testing.T, state album_sync.State, path string, info os.FileInfo, want bool) {
+	t.Helper()
+	got, err := state.IsUploaded(path, info)
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if want != got {
+		t.Errorf("%s: want: %t, got: %t", path, want, got)
+	}
+}
+
+func fileInfo(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	return info
+}
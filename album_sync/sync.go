@@ -0,0 +1,265 @@
+// Package album_sync makes an album in Google Photos match a local directory.
+//
+// The directory tree is walked looking for files with supported media extensions.
+// Files already uploaded, according to a [State], are skipped; the rest of them are
+// uploaded and created in the album, which is created if it doesn't exist:
+//
+//	state, err := album_sync.OpenFileState("/path/to/state.jsonl")
+//	if err != nil {
+//		return err
+//	}
+//	defer state.Close()
+//
+//	s, err := album_sync.New(album_sync.Config{Client: client, State: state})
+//	if err != nil {
+//		return err
+//	}
+//	report, err := s.Sync(ctx, "/path/to/photos", "My album", album_sync.Options{})
+package album_sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+)
+
+// defaultConcurrency is the number of concurrent uploads when none is configured.
+const defaultConcurrency = 4
+
+// DefaultExtensions are the file extensions of the media types supported by Google Photos.
+// See uploader.SupportedExtensions.
+var DefaultExtensions = uploader.SupportedExtensions()
+
+// Config holds the configuration of a Syncer.
+type Config struct {
+	// Client used to talk with Google Photos. Required.
+	Client *gphotos.Client
+
+	// State is the database of uploaded files. By default, an in-memory state is used,
+	// so every file is uploaded again in following runs of the program.
+	State State
+
+	// Concurrency is the maximum number of concurrent uploads. Defaults to 4.
+	Concurrency int
+
+	// Extensions are the file extensions to sync, case-insensitive. Defaults to DefaultExtensions.
+	Extensions []string
+}
+
+// Syncer uploads local directories to albums in Google Photos.
+type Syncer struct {
+	client      *gphotos.Client
+	state       State
+	concurrency int
+	extensions  map[string]bool
+}
+
+// New returns a Syncer with the given configuration.
+func New(config Config) (*Syncer, error) {
+	if config.Client == nil {
+		return nil, errors.New("client is nil")
+	}
+	if config.Concurrency < 0 {
+		return nil, errors.New("concurrency is negative")
+	}
+	s := &Syncer{
+		client:      config.Client,
+		state:       config.State,
+		concurrency: config.Concurrency,
+		extensions:  make(map[string]bool),
+	}
+	if s.state == nil {
+		s.state = NewMemoryState()
+	}
+	if s.concurrency == 0 {
+		s.concurrency = defaultConcurrency
+	}
+	extensions := config.Extensions
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+	for _, ext := range extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		s.extensions[strings.ToLower(ext)] = true
+	}
+	return s, nil
+}
+
+// Options set the options for the Sync call.
+type Options struct {
+	// DryRun reports what would be synced, without creating the album or uploading any file.
+	DryRun bool
+}
+
+// Report is the result of a sync. Files are reported by their path relative to the synced directory.
+type Report struct {
+	// AlbumID is the ID of the album. It's empty in dry runs when the album doesn't exist.
+	AlbumID string
+
+	// Uploaded are the files uploaded and created in the album. In dry runs, the files that would be uploaded.
+	Uploaded []string
+
+	// Skipped are the files already uploaded.
+	Skipped []string
+
+	// Failed are the files that couldn't be uploaded, with the reason.
+	Failed map[string]error
+}
+
+// file is a file pending to be synced.
+type file struct {
+	path string // path relative to the synced directory.
+	info fs.FileInfo
+}
+
+// Sync uploads the media files in dir, and its subdirectories, to the album with the given title.
+// Hidden files and directories are ignored.
+//
+// Files that can't be uploaded are reported as failed, and they don't stop the sync.
+// An error is returned if the directory can't be walked, the album can't be found or
+// created, the state fails, or ctx is done.
+func (s *Syncer) Sync(ctx context.Context, dir string, albumTitle string, options Options) (*Report, error) {
+	if albumTitle == "" {
+		return nil, errors.New("album title is empty")
+	}
+	report := &Report{Failed: make(map[string]error)}
+
+	files, err := s.pendingFiles(ctx, dir, report)
+	if err != nil {
+		return report, err
+	}
+
+	album, err := s.client.Albums.GetByTitle(ctx, albumTitle)
+	if err != nil && !errors.Is(err, albums.ErrAlbumNotFound) {
+		return report, fmt.Errorf("syncing album: %w", err)
+	}
+	if options.DryRun {
+		if album != nil {
+			report.AlbumID = album.ID
+		}
+		for _, f := range files {
+			report.Uploaded = append(report.Uploaded, f.path)
+		}
+		return report, nil
+	}
+	if album == nil {
+		if len(files) == 0 {
+			return report, nil
+		}
+		if album, err = s.client.Albums.Create(ctx, albumTitle); err != nil {
+			return report, fmt.Errorf("syncing album: %w", err)
+		}
+	}
+	report.AlbumID = album.ID
+
+	if err := s.upload(ctx, dir, album.ID, files, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// pendingFiles walks dir looking for media files. Files already uploaded are reported as skipped.
+func (s *Syncer) pendingFiles(ctx context.Context, dir string, report *Report) ([]file, error) {
+	var files []file
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !s.extensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			report.Failed[rel] = err
+			return nil
+		}
+		uploaded, err := s.state.IsUploaded(rel, info)
+		if err != nil {
+			return fmt.Errorf("checking state of %s: %w", rel, err)
+		}
+		if uploaded {
+			report.Skipped = append(report.Skipped, rel)
+			return nil
+		}
+		files = append(files, file{path: rel, info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// upload uploads the files, and creates them in the album, using gphotos.Client.UploadMany.
+// Files are recorded in the state as soon as they're created, so an interrupted sync keeps them.
+func (s *Syncer) upload(ctx context.Context, dir string, albumID string, files []file, report *Report) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	paths := make([]string, len(files))
+	indexes := make(map[string]int, len(files))
+	for i, f := range files {
+		paths[i] = filepath.Join(dir, f.path)
+		indexes[paths[i]] = i
+	}
+
+	// OnResult is called from this goroutine, so its state doesn't need a lock.
+	recorded := make([]bool, len(files))
+	var stateErr error
+	results, err := s.client.UploadMany(ctx, paths, gphotos.UploadManyOptions{
+		AlbumID:     albumID,
+		Concurrency: s.concurrency,
+		OnResult: func(result gphotos.UploadResult) {
+			if result.Err != nil || stateErr != nil {
+				return
+			}
+			i := indexes[result.Path]
+			f := files[i]
+			if err := s.state.MarkUploaded(f.path, f.info, result.MediaItem.ID); err != nil {
+				stateErr = fmt.Errorf("recording state of %s: %w", f.path, err)
+				cancel(stateErr)
+				return
+			}
+			recorded[i] = true
+		},
+	})
+
+	for i, result := range results {
+		f := files[i]
+		switch {
+		case recorded[i]:
+			report.Uploaded = append(report.Uploaded, f.path)
+		case result.Err != nil:
+			report.Failed[f.path] = result.Err
+		default:
+			report.Failed[f.path] = stateErr
+		}
+	}
+	if stateErr != nil {
+		return stateErr
+	}
+	return err
+}
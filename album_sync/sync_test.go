@@ -0,0 +1,216 @@
+package album_sync_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/album_sync"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+)
+
+func TestNew(t *testing.T) {
+	client, srv := newClient(t)
+	defer srv.Close()
+
+	testCases := []struct {
+		name          string
+		config        album_sync.Config
+		isErrExpected bool
+	}{
+		{"Should fail without client", album_sync.Config{}, true},
+		{"Should fail with negative concurrency", album_sync.Config{Client: client, Concurrency: -1}, true},
+		{"Should success with valid config", album_sync.Config{Client: client}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := album_sync.New(tc.config)
+			assertExpectedError(tc.isErrExpected, err, t)
+		})
+	}
+}
+
+func TestSyncer_Sync(t *testing.T) {
+	client, srv := newClient(t)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, "a.jpg", "b.mp4", filepath.Join("sub", "c.PNG"), "notes.txt", ".hidden.jpg", filepath.Join(".trash", "d.jpg"))
+
+	s, err := album_sync.New(album_sync.Config{Client: client, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	ctx := context.Background()
+	want := []string{"a.jpg", "b.mp4", filepath.Join("sub", "c.PNG")}
+
+	t.Run("Should report files to upload in dry run", func(t *testing.T) {
+		report, err := s.Sync(ctx, dir, "new-album", album_sync.Options{DryRun: true})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if !slices.Equal(want, report.Uploaded) {
+			t.Errorf("want: %v, got: %v", want, report.Uploaded)
+		}
+		if report.AlbumID != "" {
+			t.Errorf("want: no album, got: %s", report.AlbumID)
+		}
+	})
+
+	t.Run("Should upload files to a new album", func(t *testing.T) {
+		report, err := s.Sync(ctx, dir, "new-album", album_sync.Options{})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if "new-albumId" != report.AlbumID {
+			t.Errorf("want: %s, got: %s", "new-albumId", report.AlbumID)
+		}
+		if !slices.Equal(want, report.Uploaded) || len(report.Skipped) != 0 || len(report.Failed) != 0 {
+			t.Errorf("want: %v uploaded, got: %v uploaded, %v skipped, %v failed", want, report.Uploaded, report.Skipped, report.Failed)
+		}
+		// Albums in the mocked service contain all the fake media items.
+		if got := len(srv.AlbumMediaItems("new-albumId")); mocks.AvailableMediaItems+len(want) != got {
+			t.Errorf("want: %d media items in the album, got: %d", mocks.AvailableMediaItems+len(want), got)
+		}
+	})
+
+	t.Run("Should skip uploaded files", func(t *testing.T) {
+		report, err := s.Sync(ctx, dir, "new-album", album_sync.Options{})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if !slices.Equal(want, report.Skipped) || len(report.Uploaded) != 0 {
+			t.Errorf("want: %v skipped, got: %v uploaded, %v skipped", want, report.Uploaded, report.Skipped)
+		}
+	})
+
+	t.Run("Should upload modified files to an existing album", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(dir, "a.jpg"), []byte("modified content"), 0o644); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		report, err := s.Sync(ctx, dir, "fooTitle-3", album_sync.Options{})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if "fooId-3" != report.AlbumID {
+			t.Errorf("want: %s, got: %s", "fooId-3", report.AlbumID)
+		}
+		if !slices.Equal([]string{"a.jpg"}, report.Uploaded) || 2 != len(report.Skipped) {
+			t.Errorf("want: a.jpg uploaded, got: %v uploaded, %v skipped", report.Uploaded, report.Skipped)
+		}
+	})
+
+	t.Run("Should fail with empty album title", func(t *testing.T) {
+		_, err := s.Sync(ctx, dir, "", album_sync.Options{})
+		assertExpectedError(true, err, t)
+	})
+
+	t.Run("Should fail with non-existent directory", func(t *testing.T) {
+		_, err := s.Sync(ctx, filepath.Join(dir, "non-existent"), "new-album", album_sync.Options{})
+		assertExpectedError(true, err, t)
+	})
+
+	t.Run("Should fail when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := s.Sync(ctx, dir, "new-album", album_sync.Options{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("want: %v, got: %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestSyncer_Sync_Failures(t *testing.T) {
+	client, srv := newClient(t)
+	defer srv.Close()
+	client.Uploader = failingUploader{client.Uploader}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, "a.jpg", "fail.jpg")
+
+	s, err := album_sync.New(album_sync.Config{Client: client})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	report, err := s.Sync(context.Background(), dir, "new-album", album_sync.Options{})
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if !slices.Equal([]string{"a.jpg"}, report.Uploaded) {
+		t.Errorf("want: a.jpg uploaded, got: %v", report.Uploaded)
+	}
+	if _, ok := report.Failed["fail.jpg"]; !ok || 1 != len(report.Failed) {
+		t.Errorf("want: fail.jpg failed, got: %v", report.Failed)
+	}
+
+	t.Run("Should retry failed files", func(t *testing.T) {
+		report, err := s.Sync(context.Background(), dir, "new-album", album_sync.Options{})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if _, ok := report.Failed["fail.jpg"]; !ok || len(report.Uploaded) != 0 || 1 != len(report.Skipped) {
+			t.Errorf("want: a.jpg skipped and fail.jpg failed, got: %v uploaded, %v skipped, %v failed", report.Uploaded, report.Skipped, report.Failed)
+		}
+	})
+
+}
+
+// failingUploader fails to upload files named fail.*.
+type failingUploader struct {
+	gphotos.MediaUploader
+}
+
+func (u failingUploader) UploadFile(ctx context.Context, filePath string) (string, error) {
+	if strings.HasPrefix(filepath.Base(filePath), "fail.") {
+		return "", errors.New("upload failed")
+	}
+	return u.MediaUploader.UploadFile(ctx, filePath)
+}
+
+func newClient(t *testing.T) (*gphotos.Client, *mocks.MockedGooglePhotosService) {
+	t.Helper()
+	srv := mocks.NewMockedGooglePhotosService()
+	client, err := gphotos.NewClientWithBaseURL(http.DefaultClient, srv.URL())
+	if err != nil {
+		srv.Close()
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u, err := uploader.NewSimpleUploader(http.DefaultClient)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+	client.Uploader = u
+	return client, srv
+}
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+	}
+}
+
+func assertExpectedError(isErrExpected bool, err error, t *testing.T) {
+	if isErrExpected && err == nil {
+		t.Fatalf("error was expected, but not produced")
+	}
+	if !isErrExpected && err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/library"
 	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
 	"google.golang.org/api/googleapi"
 	"net/http"
+	"slices"
+	"strings"
 )
 
 // An Album represents a Google Photos album.
@@ -65,10 +68,20 @@ type Service struct {
 
 // PhotosLibraryClient represents a Google Photos client using `gphotosuploader/googlemirror/api/photoslibrary`.
 type PhotosLibraryClient interface {
+	AddEnrichment(albumId string, addEnrichmentToAlbumRequest *photoslibrary.AddEnrichmentToAlbumRequest) *photoslibrary.AlbumsAddEnrichmentCall
 	BatchAddMediaItems(albumId string, albumBatchAddMediaItemsRequest *photoslibrary.AlbumBatchAddMediaItemsRequest) *photoslibrary.AlbumBatchAddMediaItemsCall
 	Create(createAlbumRequest *photoslibrary.CreateAlbumRequest) *photoslibrary.AlbumsCreateCall
 	Get(albumId string) *photoslibrary.AlbumsGetCall
 	List() *photoslibrary.AlbumsListCall
+	Patch(albumId string, album *library.AlbumPatch) *library.AlbumsPatchCall
+	BatchRemoveMediaItems(albumId string, albumBatchRemoveMediaItemsRequest *library.AlbumBatchRemoveMediaItemsRequest) *library.AlbumBatchRemoveMediaItemsCall
+}
+
+// photosLibraryClient joins the albums calls from `gphotosuploader/googlemirror/api/photoslibrary`
+// with the ones missing there.
+type photosLibraryClient struct {
+	*photoslibrary.AlbumsService
+	*library.Albums
 }
 
 // New returns an albums Google Photos service.
@@ -86,27 +99,80 @@ func New(config Config) (*Service, error) {
 		s.UserAgent = config.UserAgent
 	}
 
+	l, err := library.New(config.Client)
+	if err != nil {
+		return nil, fmt.Errorf("creating service: %w", err)
+	}
+	l.BasePath = s.BasePath
+	l.UserAgent = s.UserAgent
+
 	service := &Service{
-		photos: s.Albums,
+		photos: photosLibraryClient{s.Albums, l.Albums},
 	}
 
 	return service, nil
 }
 
 // AddMediaItems add one or more existing media items to an existing Album.
+// Media items are added in batches of up to 50 items per call.
+//
+// If a batch fails, the remaining batches are not processed and a [*BatchError] is returned
+// listing which media items were added and which were not.
 func (s *Service) AddMediaItems(ctx context.Context, albumID string, mediaItemIDs []string) error {
+	err := inBatches(mediaItemIDs, func(batch []string) error {
+		req := &photoslibrary.AlbumBatchAddMediaItemsRequest{
+			MediaItemIds: batch,
+		}
+		_, err := s.photos.BatchAddMediaItems(albumID, req).Context(ctx).Do()
+		return translateGoogleAPIError(err)
+	})
+	if err != nil {
+		return fmt.Errorf("adding media items to album: %w", err)
+	}
+	return nil
+}
 
-	// TODO: There's a limitPerPage of 50 media items per call. Split in multiple calls if more are provided.
+// maxMediaItemsPerBatch is the maximum number of media items that can be
+// added to or removed from an album in a single call.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/albums/batchRemoveMediaItems.
+const maxMediaItemsPerBatch = 50
 
-	req := &photoslibrary.AlbumBatchAddMediaItemsRequest{
-		MediaItemIds: mediaItemIDs,
-	}
-	_, err := s.photos.BatchAddMediaItems(albumID, req).Context(ctx).Do()
+// RemoveMediaItems removes one or more media items from an existing Album.
+// The media items are not deleted from the user's library.
+// Media items are removed in batches of up to 50 items per call.
+//
+// If a batch fails, the remaining batches are not processed and a [*BatchError] is returned
+// listing which media items were removed and which were not.
+// Returns [ErrAlbumNotFound] if the album does not exist.
+func (s *Service) RemoveMediaItems(ctx context.Context, albumID string, mediaItemIDs []string) error {
+	err := inBatches(mediaItemIDs, func(batch []string) error {
+		req := &library.AlbumBatchRemoveMediaItemsRequest{
+			MediaItemIds: batch,
+		}
+		return translateGoogleAPIError(s.photos.BatchRemoveMediaItems(albumID, req).Context(ctx).Do())
+	})
 	if err != nil {
-		return fmt.Errorf("adding media items to album: %w", err)
+		return fmt.Errorf("removing media items from album: %w", err)
 	}
 	return nil
+}
 
+// inBatches calls fn sequentially with batches of up to maxMediaItemsPerBatch media item IDs.
+// It stops at the first failed batch, returning a [*BatchError].
+func inBatches(mediaItemIDs []string, fn func(batch []string) error) error {
+	processed := 0
+	for batch := range slices.Chunk(mediaItemIDs, maxMediaItemsPerBatch) {
+		if err := fn(batch); err != nil {
+			return &BatchError{
+				Succeeded: mediaItemIDs[:processed],
+				Failed:    mediaItemIDs[processed:],
+				Err:       err,
+			}
+		}
+		processed += len(batch)
+	}
+	return nil
 }
 
 // Create creates an album in Google Photos.
@@ -133,6 +199,51 @@ func (s *Service) GetById(ctx context.Context, albumID string) (*Album, error) {
 	return nil, fmt.Errorf("getting album by id: %w", translateGoogleAPIError(err))
 }
 
+// PatchOptions set the album fields to be updated by the Patch call.
+// Empty fields are left unchanged.
+type PatchOptions struct {
+	// Title: New name of the album. It should not be more than 500 characters.
+	Title string
+
+	// CoverPhotoMediaItemID: Identifier of the media item to be used as the cover photo.
+	// It must be a media item in the album.
+	CoverPhotoMediaItemID string
+}
+
+// maxAlbumTitleLength is the maximum length of an album title.
+const maxAlbumTitleLength = 500
+
+// Patch updates the title and/or the cover photo of the album specified by the given album id.
+// It returns the updated album.
+//
+// Returns [ErrAlbumNotFound] if the album does not exist.
+func (s *Service) Patch(ctx context.Context, albumID string, options PatchOptions) (*Album, error) {
+	var updateMask []string
+	if options.Title != "" {
+		if len([]rune(options.Title)) > maxAlbumTitleLength {
+			return nil, fmt.Errorf("updating album: title is longer than %d characters", maxAlbumTitleLength)
+		}
+		updateMask = append(updateMask, "title")
+	}
+	if options.CoverPhotoMediaItemID != "" {
+		updateMask = append(updateMask, "coverPhotoMediaItemId")
+	}
+	if len(updateMask) == 0 {
+		return nil, errors.New("updating album: no fields to update")
+	}
+
+	req := &library.AlbumPatch{
+		Title:                 options.Title,
+		CoverPhotoMediaItemId: options.CoverPhotoMediaItemID,
+	}
+	res, err := s.photos.Patch(albumID, req).UpdateMask(strings.Join(updateMask, ",")).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("updating album: %w", translateGoogleAPIError(err))
+	}
+	album := toAlbum(res)
+	return &album, nil
+}
+
 func translateGoogleAPIError(err error) error {
 	// Check if the error is of type *googleapi.Error
 	var apiErr *googleapi.Error
@@ -3,9 +3,11 @@ package albums_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -73,6 +75,111 @@ func TestAlbumsService_AddMediaItems(t *testing.T) {
 	}
 }
 
+func TestAlbumsService_AddMediaItems_InBatches(t *testing.T) {
+	t.Run("Should add more than 50 media items to album", func(t *testing.T) {
+		srv := mocks.NewMockedGooglePhotosService()
+		defer srv.Close()
+
+		s, err := albums.New(albums.Config{Client: http.DefaultClient, BaseURL: srv.URL()})
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+
+		mediaItems := fakeMediaItemIDs(1000, 1120)
+		if err := s.AddMediaItems(context.Background(), mocks.ExistingAlbum.Id, mediaItems); err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+
+		want := mocks.AvailableMediaItems + len(mediaItems)
+		if got := len(srv.AlbumMediaItems(mocks.ExistingAlbum.Id)); want != got {
+			t.Errorf("want: %d, got: %d", want, got)
+		}
+	})
+
+	t.Run("Should return BatchError if a batch fails", func(t *testing.T) {
+		srv := mocks.NewMockedGooglePhotosService()
+		defer srv.Close()
+
+		s, err := albums.New(albums.Config{Client: http.DefaultClient, BaseURL: srv.URL()})
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+
+		mediaItems := fakeMediaItemIDs(1000, 1120)
+		mediaItems[75] = mocks.ShouldMakeAPIFailMediaItem
+		err = s.AddMediaItems(context.Background(), mocks.ExistingAlbum.Id, mediaItems)
+
+		var batchErr *albums.BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("want: *albums.BatchError, got: %v", err)
+		}
+		if len(batchErr.Succeeded) != 50 {
+			t.Errorf("want: %d, got: %d", 50, len(batchErr.Succeeded))
+		}
+		if len(batchErr.Failed) != 70 {
+			t.Errorf("want: %d, got: %d", 70, len(batchErr.Failed))
+		}
+		if batchErr.Failed[0] != mediaItems[50] {
+			t.Errorf("want: %s, got: %s", mediaItems[50], batchErr.Failed[0])
+		}
+	})
+}
+
+func TestAlbumsService_RemoveMediaItems(t *testing.T) {
+	testCases := []struct {
+		name          string
+		album         string
+		mediaItems    []string
+		wantRemaining int
+		isErrExpected bool
+	}{
+		{"Should remove media items from album", mocks.ExistingAlbum.Id, fakeMediaItemIDs(0, 2), mocks.AvailableMediaItems - 2, false},
+		{"Should remove more than 50 media items from album", mocks.ExistingAlbum.Id, fakeMediaItemIDs(0, 60), mocks.AvailableMediaItems - 60, false},
+		{"Should return error if media item is not in the album", mocks.ExistingAlbum.Id, []string{"non-existent"}, mocks.AvailableMediaItems, true},
+		{"Should return error if album does not exist", "non-existent", fakeMediaItemIDs(0, 2), 0, true},
+		{"Should return error if API fails", mocks.ShouldFailAlbum.Id, fakeMediaItemIDs(0, 2), 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewMockedGooglePhotosService()
+			defer srv.Close()
+
+			c := albums.Config{
+				Client:  http.DefaultClient,
+				BaseURL: srv.URL(),
+			}
+			s, err := albums.New(c)
+			if err != nil {
+				t.Fatalf("error was not expected at this point: %s", err)
+			}
+
+			err = s.RemoveMediaItems(context.Background(), tc.album, tc.mediaItems)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if tc.album == mocks.ExistingAlbum.Id {
+				if got := len(srv.AlbumMediaItems(tc.album)); tc.wantRemaining != got {
+					t.Errorf("want: %d, got: %d", tc.wantRemaining, got)
+				}
+			}
+		})
+	}
+
+	t.Run("Should return ErrAlbumNotFound if album does not exist", func(t *testing.T) {
+		srv := mocks.NewMockedGooglePhotosService()
+		defer srv.Close()
+
+		s, err := albums.New(albums.Config{Client: http.DefaultClient, BaseURL: srv.URL()})
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+
+		err = s.RemoveMediaItems(context.Background(), "non-existent", []string{"fooId-0"})
+		if !errors.Is(err, albums.ErrAlbumNotFound) {
+			t.Errorf("want: %v, got: %v", albums.ErrAlbumNotFound, err)
+		}
+	})
+}
+
 func TestAlbumsService_Create(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -178,6 +285,49 @@ func TestAlbumsService_GetByTitle(t *testing.T) {
 	}
 }
 
+func TestAlbumsService_Patch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		albumID       string
+		options       albums.PatchOptions
+		wantTitle     string
+		expectedError error
+		isErrExpected bool
+	}{
+		{"Should update the title", mocks.ExistingAlbum.Id, albums.PatchOptions{Title: "barTitle"}, "barTitle", nil, false},
+		{"Should update the cover photo", mocks.ExistingAlbum.Id, albums.PatchOptions{CoverPhotoMediaItemID: "fooId-1"}, mocks.ExistingAlbum.Title, nil, false},
+		{"Should return error if there is nothing to update", mocks.ExistingAlbum.Id, albums.PatchOptions{}, "", nil, true},
+		{"Should return error if title is too long", mocks.ExistingAlbum.Id, albums.PatchOptions{Title: strings.Repeat("a", 501)}, "", nil, true},
+		{"Should return ErrAlbumNotFound if album does not exist", "non-existent", albums.PatchOptions{Title: "barTitle"}, "", albums.ErrAlbumNotFound, true},
+		{"Should return error if API fails", mocks.ShouldFailAlbum.Id, albums.PatchOptions{Title: "barTitle"}, "", nil, true},
+	}
+
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	c := albums.Config{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL(),
+	}
+	s, err := albums.New(c)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.Patch(context.Background(), tc.albumID, tc.options)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("not expected error, want: %v, got: %v", tc.expectedError, err)
+			}
+			if err == nil && tc.wantTitle != got.Title {
+				t.Errorf("want: %s, got: %s", tc.wantTitle, got.Title)
+			}
+		})
+	}
+}
+
 func TestAlbumsService_List(t *testing.T) {
 	srv := mocks.NewMockedGooglePhotosService()
 	defer srv.Close()
@@ -279,6 +429,15 @@ func TestService_PaginatedList(t *testing.T) {
 	}
 }
 
+// fakeMediaItemIDs returns the IDs of the mocked media items in the range [from, to).
+func fakeMediaItemIDs(from, to int) []string {
+	ids := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		ids = append(ids, fmt.Sprintf("fooId-%d", i))
+	}
+	return ids
+}
+
 func assertExpectedError(isErrExpected bool, err error, t *testing.T) {
 	if isErrExpected && err == nil {
 		t.Fatalf("error was expected, but not produced")
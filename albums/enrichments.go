@@ -0,0 +1,224 @@
+package albums
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// An Enrichment represents an enrichment item that can be added to an album.
+// It's implemented by [TextEnrichment], [LocationEnrichment] and [MapEnrichment].
+//
+// See: https://developers.google.com/photos/library/guides/add-enrichments.
+type Enrichment interface {
+	toNewEnrichmentItem() (*photoslibrary.NewEnrichmentItem, error)
+}
+
+// A TextEnrichment represents a text block added to an album.
+type TextEnrichment struct {
+	// Text: Text for this enrichment item.
+	Text string
+}
+
+func (e TextEnrichment) toNewEnrichmentItem() (*photoslibrary.NewEnrichmentItem, error) {
+	if e.Text == "" {
+		return nil, errors.New("text enrichment is empty")
+	}
+	return &photoslibrary.NewEnrichmentItem{
+		TextEnrichment: &photoslibrary.TextEnrichment{Text: e.Text},
+	}, nil
+}
+
+// A Location represents a physical location.
+type Location struct {
+	// Name: Name of the location to be displayed.
+	Name string
+
+	// Latitude: The latitude in degrees. It must be in the range [-90.0, +90.0].
+	Latitude float64
+
+	// Longitude: The longitude in degrees. It must be in the range [-180.0, +180.0].
+	Longitude float64
+}
+
+func (l Location) toLocation() (*photoslibrary.Location, error) {
+	if l.Latitude < -90 || l.Latitude > 90 {
+		return nil, fmt.Errorf("latitude %f out of range", l.Latitude)
+	}
+	if l.Longitude < -180 || l.Longitude > 180 {
+		return nil, fmt.Errorf("longitude %f out of range", l.Longitude)
+	}
+	return &photoslibrary.Location{
+		LocationName: l.Name,
+		Latlng: &photoslibrary.LatLng{
+			Latitude:  l.Latitude,
+			Longitude: l.Longitude,
+			// A location on the Equator or on the Greenwich meridian is valid.
+			ForceSendFields: []string{"Latitude", "Longitude"},
+		},
+	}, nil
+}
+
+// A LocationEnrichment represents a location pin added to an album.
+type LocationEnrichment struct {
+	// Location: Location for this enrichment item.
+	Location Location
+}
+
+func (e LocationEnrichment) toNewEnrichmentItem() (*photoslibrary.NewEnrichmentItem, error) {
+	location, err := e.Location.toLocation()
+	if err != nil {
+		return nil, fmt.Errorf("location enrichment: %w", err)
+	}
+	return &photoslibrary.NewEnrichmentItem{
+		LocationEnrichment: &photoslibrary.LocationEnrichment{Location: location},
+	}, nil
+}
+
+// A MapEnrichment represents a map showing a route added to an album.
+type MapEnrichment struct {
+	// Origin: Origin location for this enrichment item.
+	Origin Location
+
+	// Destination: Destination location for this enrichment item.
+	Destination Location
+}
+
+func (e MapEnrichment) toNewEnrichmentItem() (*photoslibrary.NewEnrichmentItem, error) {
+	origin, err := e.Origin.toLocation()
+	if err != nil {
+		return nil, fmt.Errorf("map enrichment origin: %w", err)
+	}
+	destination, err := e.Destination.toLocation()
+	if err != nil {
+		return nil, fmt.Errorf("map enrichment destination: %w", err)
+	}
+	return &photoslibrary.NewEnrichmentItem{
+		MapEnrichment: &photoslibrary.MapEnrichment{
+			Origin:      origin,
+			Destination: destination,
+		},
+	}, nil
+}
+
+// PositionType is the type of position in an album.
+type PositionType string
+
+const (
+	// PositionLastInAlbum places the item at the end of the album. It's the default value.
+	PositionLastInAlbum PositionType = "LAST_IN_ALBUM"
+
+	// PositionFirstInAlbum places the item at the beginning of the album.
+	PositionFirstInAlbum PositionType = "FIRST_IN_ALBUM"
+
+	// PositionAfterMediaItem places the item after a media item.
+	PositionAfterMediaItem PositionType = "AFTER_MEDIA_ITEM"
+
+	// PositionAfterEnrichmentItem places the item after an enrichment item.
+	PositionAfterEnrichmentItem PositionType = "AFTER_ENRICHMENT_ITEM"
+)
+
+// An AlbumPosition specifies a position in an album.
+// The zero value places the item at the end of the album.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/AlbumPosition.
+type AlbumPosition struct {
+	// Position: Type of position.
+	Position PositionType
+
+	// RelativeMediaItemID: The media item to which the position is relative to.
+	// Only used when Position is PositionAfterMediaItem.
+	RelativeMediaItemID string
+
+	// RelativeEnrichmentItemID: The enrichment item to which the position is relative to.
+	// Only used when Position is PositionAfterEnrichmentItem.
+	RelativeEnrichmentItemID string
+}
+
+// FirstInAlbum returns the position at the beginning of an album.
+func FirstInAlbum() AlbumPosition {
+	return AlbumPosition{Position: PositionFirstInAlbum}
+}
+
+// LastInAlbum returns the position at the end of an album.
+func LastInAlbum() AlbumPosition {
+	return AlbumPosition{Position: PositionLastInAlbum}
+}
+
+// AfterMediaItem returns the position after the specified media item.
+func AfterMediaItem(mediaItemID string) AlbumPosition {
+	return AlbumPosition{Position: PositionAfterMediaItem, RelativeMediaItemID: mediaItemID}
+}
+
+// AfterEnrichmentItem returns the position after the specified enrichment item.
+func AfterEnrichmentItem(enrichmentItemID string) AlbumPosition {
+	return AlbumPosition{Position: PositionAfterEnrichmentItem, RelativeEnrichmentItemID: enrichmentItemID}
+}
+
+// Validate returns an error if the position is not valid.
+func (p AlbumPosition) Validate() error {
+	switch p.Position {
+	case "", PositionLastInAlbum, PositionFirstInAlbum:
+		if p.RelativeMediaItemID != "" || p.RelativeEnrichmentItemID != "" {
+			return fmt.Errorf("invalid album position: %s does not accept a relative item", p.Position)
+		}
+	case PositionAfterMediaItem:
+		if p.RelativeMediaItemID == "" || p.RelativeEnrichmentItemID != "" {
+			return errors.New("invalid album position: AFTER_MEDIA_ITEM requires only a relative media item")
+		}
+	case PositionAfterEnrichmentItem:
+		if p.RelativeEnrichmentItemID == "" || p.RelativeMediaItemID != "" {
+			return errors.New("invalid album position: AFTER_ENRICHMENT_ITEM requires only a relative enrichment item")
+		}
+	default:
+		return fmt.Errorf("invalid album position: unknown type %q", p.Position)
+	}
+	return nil
+}
+
+// ToAlbumPosition returns the position in the format of the API requests.
+// The zero value is the position at the end of the album.
+func (p AlbumPosition) ToAlbumPosition() *photoslibrary.AlbumPosition {
+	position := p.Position
+	if position == "" {
+		position = PositionLastInAlbum
+	}
+	return &photoslibrary.AlbumPosition{
+		Position:                 string(position),
+		RelativeMediaItemId:      p.RelativeMediaItemID,
+		RelativeEnrichmentItemId: p.RelativeEnrichmentItemID,
+	}
+}
+
+// AddEnrichment adds an enrichment at the specified position in an album,
+// and returns the ID of the created enrichment item.
+// Enrichments can only be added to albums created by this app.
+//
+// Returns [ErrAlbumNotFound] if the album does not exist.
+func (s *Service) AddEnrichment(ctx context.Context, albumID string, enrichment Enrichment, position AlbumPosition) (string, error) {
+	if enrichment == nil {
+		return "", errors.New("adding enrichment to album: enrichment is nil")
+	}
+	item, err := enrichment.toNewEnrichmentItem()
+	if err != nil {
+		return "", fmt.Errorf("adding enrichment to album: %w", err)
+	}
+	if err := position.Validate(); err != nil {
+		return "", fmt.Errorf("adding enrichment to album: %w", err)
+	}
+
+	req := &photoslibrary.AddEnrichmentToAlbumRequest{
+		NewEnrichmentItem: item,
+		AlbumPosition:     position.ToAlbumPosition(),
+	}
+	res, err := s.photos.AddEnrichment(albumID, req).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("adding enrichment to album: %w", translateGoogleAPIError(err))
+	}
+	if res.EnrichmentItem == nil {
+		return "", errors.New("adding enrichment to album: empty enrichment item in response")
+	}
+	return res.EnrichmentItem.Id, nil
+}
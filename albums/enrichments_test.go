@@ -0,0 +1,106 @@
+package albums_test
+
+import (
+	"context"
+	"errors"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAlbumsService_AddEnrichment(t *testing.T) {
+	testCases := []struct {
+		name          string
+		album         string
+		enrichment    albums.Enrichment
+		position      albums.AlbumPosition
+		isErrExpected bool
+	}{
+		{"Should add a text enrichment", mocks.ExistingAlbum.Id, albums.TextEnrichment{Text: "Day 1"}, albums.AlbumPosition{}, false},
+		{"Should add a location enrichment", mocks.ExistingAlbum.Id, albums.LocationEnrichment{Location: albums.Location{Name: "Null Island"}}, albums.FirstInAlbum(), false},
+		{"Should add a map enrichment", mocks.ExistingAlbum.Id, albums.MapEnrichment{Origin: albums.Location{Name: "Madrid", Latitude: 40.41, Longitude: -3.70}, Destination: albums.Location{Name: "Paris", Latitude: 48.85, Longitude: 2.35}}, albums.AfterMediaItem("fooId-1"), false},
+		{"Should return error if enrichment is nil", mocks.ExistingAlbum.Id, nil, albums.AlbumPosition{}, true},
+		{"Should return error if text is empty", mocks.ExistingAlbum.Id, albums.TextEnrichment{}, albums.AlbumPosition{}, true},
+		{"Should return error if latitude is out of range", mocks.ExistingAlbum.Id, albums.LocationEnrichment{Location: albums.Location{Latitude: 91}}, albums.AlbumPosition{}, true},
+		{"Should return error if position is invalid", mocks.ExistingAlbum.Id, albums.TextEnrichment{Text: "Day 1"}, albums.AlbumPosition{Position: albums.PositionAfterMediaItem}, true},
+		{"Should return error if album does not exist", "non-existent", albums.TextEnrichment{Text: "Day 1"}, albums.AlbumPosition{}, true},
+		{"Should return error if API fails", mocks.ShouldFailAlbum.Id, albums.TextEnrichment{Text: "Day 1"}, albums.AlbumPosition{}, true},
+	}
+
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	c := albums.Config{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL(),
+	}
+	s, err := albums.New(c)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.AddEnrichment(context.Background(), tc.album, tc.enrichment, tc.position)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if err == nil && got == "" {
+				t.Errorf("want: enrichment item id, got: empty")
+			}
+		})
+	}
+
+	t.Run("Should return ErrAlbumNotFound if album does not exist", func(t *testing.T) {
+		_, err := s.AddEnrichment(context.Background(), "non-existent", albums.TextEnrichment{Text: "Day 1"}, albums.LastInAlbum())
+		if !errors.Is(err, albums.ErrAlbumNotFound) {
+			t.Errorf("want: %v, got: %v", albums.ErrAlbumNotFound, err)
+		}
+	})
+}
+
+func TestAlbumPosition_Validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		position      albums.AlbumPosition
+		isErrExpected bool
+	}{
+		{"Zero value is valid", albums.AlbumPosition{}, false},
+		{"First in album is valid", albums.FirstInAlbum(), false},
+		{"Last in album is valid", albums.LastInAlbum(), false},
+		{"After media item is valid", albums.AfterMediaItem("foo"), false},
+		{"After enrichment item is valid", albums.AfterEnrichmentItem("foo"), false},
+		{"After media item without media item is invalid", albums.AfterMediaItem(""), true},
+		{"After enrichment item without enrichment item is invalid", albums.AfterEnrichmentItem(""), true},
+		{"First in album with relative item is invalid", albums.AlbumPosition{Position: albums.PositionFirstInAlbum, RelativeMediaItemID: "foo"}, true},
+		{"Unknown position is invalid", albums.AlbumPosition{Position: "foo"}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertExpectedError(tc.isErrExpected, tc.position.Validate(), t)
+		})
+	}
+}
+
+func TestAlbumPosition_ToAlbumPosition(t *testing.T) {
+	testCases := []struct {
+		name     string
+		position albums.AlbumPosition
+		want     photoslibrary.AlbumPosition
+	}{
+		{"Zero value is last in album", albums.AlbumPosition{}, photoslibrary.AlbumPosition{Position: "LAST_IN_ALBUM"}},
+		{"First in album", albums.FirstInAlbum(), photoslibrary.AlbumPosition{Position: "FIRST_IN_ALBUM"}},
+		{"After media item", albums.AfterMediaItem("foo"), photoslibrary.AlbumPosition{Position: "AFTER_MEDIA_ITEM", RelativeMediaItemId: "foo"}},
+		{"After enrichment item", albums.AfterEnrichmentItem("foo"), photoslibrary.AlbumPosition{Position: "AFTER_ENRICHMENT_ITEM", RelativeEnrichmentItemId: "foo"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.position.ToAlbumPosition(); !reflect.DeepEqual(&tc.want, got) {
+				t.Errorf("want: %+v, got: %+v", tc.want, got)
+			}
+		})
+	}
+}
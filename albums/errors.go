@@ -1,8 +1,35 @@
 package albums
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrAlbumNotFound is the error returned when an album is not found.
 	ErrAlbumNotFound = errors.New("album not found")
 )
+
+// BatchError is the error returned when a batch operation over the media items of an
+// album has been partially completed. Media items are processed in batches, and
+// the operation stops at the first failed batch.
+//
+// Succeeded and Failed can be used to retry only the media items that were not processed.
+type BatchError struct {
+	// Succeeded holds the IDs of the media items that were processed.
+	Succeeded []string
+
+	// Failed holds the IDs of the media items that were not processed.
+	Failed []string
+
+	// Err is the error that made the batch fail.
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d media items failed: %s", len(e.Failed), len(e.Succeeded)+len(e.Failed), e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,23 @@
+package albums
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/pagination"
+)
+
+// All returns an iterator over all the albums created by this app. Pages are
+// retrieved as the iteration goes on, so albums are not kept in memory.
+//
+// The iteration stops after yielding the first error, e.g. when ctx is cancelled.
+func (s *Service) All(ctx context.Context) iter.Seq2[Album, error] {
+	return pagination.Seq(ctx, "", func(ctx context.Context, pageToken string) ([]Album, string, error) {
+		res, err := s.photos.List().PageSize(maxAlbumsPerPage).PageToken(pageToken).ExcludeNonAppCreatedData().Context(ctx).Do()
+		if err != nil {
+			return nil, "", fmt.Errorf("listing albums: %w", err)
+		}
+		return toAlbums(res.Albums), res.NextPageToken, nil
+	})
+}
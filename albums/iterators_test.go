@@ -0,0 +1,60 @@
+package albums_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+)
+
+func TestAlbumsService_All(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	s, err := albums.New(albums.Config{Client: http.DefaultClient, BaseURL: srv.URL()})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	t.Run("Should iterate over all albums", func(t *testing.T) {
+		got := 0
+		for album, err := range s.All(context.Background()) {
+			if err != nil {
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if album.ID == "" {
+				t.Errorf("want: album ID, got: empty")
+			}
+			got++
+		}
+		if mocks.AvailableAlbums != got {
+			t.Errorf("want: %d, got: %d", mocks.AvailableAlbums, got)
+		}
+	})
+
+	t.Run("Should stop when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		got := 0
+		var gotErr error
+		for _, err := range s.All(ctx) {
+			if err != nil {
+				gotErr = err
+				break
+			}
+			got++
+			if got == 10 {
+				cancel()
+			}
+		}
+		if !errors.Is(gotErr, context.Canceled) {
+			t.Errorf("want: %v, got: %v", context.Canceled, gotErr)
+		}
+		if 10 != got {
+			t.Errorf("want: %d, got: %d", 10, got)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/shared_albums"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
 	"net/http"
 )
@@ -20,9 +21,16 @@ type Client struct {
 	// Uploader implementation used when uploading files to Google Photos.
 	Uploader MediaUploader
 
+	// UploadIndex, if set, records the media items created by the Upload* methods and
+	// UploadMany, keyed by the content hash of the files. Files with the same content as an
+	// already uploaded one are not uploaded again, and the existing media item is returned.
+	// Media items that can't be recorded are returned in an *UploadIndexError.
+	UploadIndex UploadIndex
+
 	// Services used for talking to different parts of the Google Photos API.
-	Albums     AlbumsService
-	MediaItems MediaItemsService
+	Albums       AlbumsService
+	MediaItems   MediaItemsService
+	SharedAlbums SharedAlbumsService
 }
 
 // NewClient returns a new Google Photos API client.
@@ -68,14 +76,26 @@ func NewClientWithBaseURL(httpClient *http.Client, baseURL string) (*Client, err
 		return nil, err
 	}
 
+	// Create the Shared Albums Service using default values.
+	sharedAlbumsConfig := shared_albums.Config{
+		Client:    httpClient,
+		BaseURL:   baseURL,
+		UserAgent: defaultUserAgent,
+	}
+	sharedAlbumsService, err := shared_albums.New(sharedAlbumsConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	simpleUploader, err := uploader.NewSimpleUploader(httpClient)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		Uploader:   simpleUploader,
-		Albums:     albumsService,
-		MediaItems: mediaItemsService,
+		Uploader:     simpleUploader,
+		Albums:       albumsService,
+		MediaItems:   mediaItemsService,
+		SharedAlbums: sharedAlbumsService,
 	}, nil
 }
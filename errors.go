@@ -1,5 +1,11 @@
 package gphotos
 
+import (
+	"fmt"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+)
+
 // ErrDailyQuotaExceeded is returned when the Google Photos API 'All request' per
 // day quota is exceeded.
 //
@@ -9,3 +15,22 @@ type ErrDailyQuotaExceeded struct{}
 func (e *ErrDailyQuotaExceeded) Error() string {
 	return "daily quota exceeded"
 }
+
+// UploadIndexError is returned when a media item has been created, but it couldn't be
+// recorded in the Client.UploadIndex. The media item exists in Google Photos, so it's
+// kept in the error: uploading the same content again would create a duplicate.
+type UploadIndexError struct {
+	// MediaItem is the created media item.
+	MediaItem *media_items.MediaItem
+
+	// Err is the error of the UploadIndex.
+	Err error
+}
+
+func (e *UploadIndexError) Error() string {
+	return fmt.Sprintf("recording uploaded media item %s: %v", e.MediaItem.ID, e.Err)
+}
+
+func (e *UploadIndexError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,318 @@
+// Package export mirrors a Google Photos library, or one of its albums, to a local directory.
+//
+// Media items are written with a YYYY/MM/filename layout based on their creation time,
+// which is also set as the file modification time. A manifest of the exported media items
+// is kept in the destination directory, so following exports only download the new ones.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+)
+
+// MediaItemsService represents the media items calls used by the exporter.
+// It's implemented by [media_items.Service].
+type MediaItemsService interface {
+	All(ctx context.Context) iter.Seq2[media_items.MediaItem, error]
+	ByAlbum(ctx context.Context, albumId string) iter.Seq2[media_items.MediaItem, error]
+	Download(ctx context.Context, mediaItem media_items.MediaItem, options media_items.DownloadOptions) (io.ReadCloser, error)
+}
+
+// defaultConcurrency is the number of parallel downloads when it's not configured.
+const defaultConcurrency = 4
+
+// Config holds the configuration parameters for the exporter.
+type Config struct {
+	// MediaItems service used to list and download the media items.
+	MediaItems MediaItemsService
+
+	// Dir is the destination directory. It's created if it doesn't exist.
+	Dir string
+
+	// [Optional] AlbumID exports only the media items in the album.
+	AlbumID string
+
+	// [Optional] Concurrency is the maximum number of parallel downloads. Defaults to 4.
+	Concurrency int
+
+	// [Optional] OnProgress is called with the progress of every download. Parallel
+	// downloads call it concurrently. See media_items.DownloadOptions.
+	OnProgress media_items.DownloadProgressFunc
+}
+
+// Exporter exports media items to a local directory.
+type Exporter struct {
+	mediaItems  MediaItemsService
+	dir         string
+	albumID     string
+	concurrency int
+	onProgress  media_items.DownloadProgressFunc
+}
+
+// New returns an exporter with the given configuration.
+func New(config Config) (*Exporter, error) {
+	if config.MediaItems == nil {
+		return nil, errors.New("media items service is nil")
+	}
+	if config.Dir == "" {
+		return nil, errors.New("destination directory is empty")
+	}
+	if config.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency %d", config.Concurrency)
+	}
+	concurrency := config.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultConcurrency
+	}
+	return &Exporter{
+		mediaItems:  config.MediaItems,
+		dir:         config.Dir,
+		albumID:     config.AlbumID,
+		concurrency: concurrency,
+		onProgress:  config.OnProgress,
+	}, nil
+}
+
+// Report holds the result of an export.
+type Report struct {
+	// Exported holds the IDs of the media items downloaded by this export.
+	Exported []string
+
+	// Skipped holds the IDs of the media items exported by previous exports.
+	Skipped []string
+
+	// Failed maps the IDs of the media items that couldn't be exported with the reason.
+	Failed map[string]error
+}
+
+func (r *Report) exported(mediaItemID string) {
+	r.Exported = append(r.Exported, mediaItemID)
+}
+
+func (r *Report) failed(mediaItemID string, err error) {
+	if r.Failed == nil {
+		r.Failed = make(map[string]error)
+	}
+	r.Failed[mediaItemID] = err
+}
+
+// job is a media item to be downloaded to path, relative to the destination directory.
+type job struct {
+	mediaItem media_items.MediaItem
+	path      string
+}
+
+// Export downloads the media items that were not exported yet.
+//
+// Media items that fail to download are reported in Report.Failed, and they are
+// retried by the next export. An error is returned if listing the media items
+// fails or ctx is cancelled. Every media item is recorded in the manifest as soon
+// as it's exported, so interrupted exports don't download it again. Files written by an
+// export interrupted before recording them are found by path and modification time, and
+// recorded instead of being exported again.
+func (e *Exporter) Export(ctx context.Context) (*Report, error) {
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("exporting media items: %w", err)
+	}
+	m, err := openManifest(e.dir)
+	if err != nil {
+		return nil, fmt.Errorf("exporting media items: %w", err)
+	}
+
+	report := &Report{}
+	var mu sync.Mutex // protects report
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for range e.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				err := e.download(ctx, j)
+				if err == nil {
+					err = m.add(j.mediaItem.ID, j.path)
+				}
+				mu.Lock()
+				if err != nil {
+					report.failed(j.mediaItem.ID, err)
+				} else {
+					report.exported(j.mediaItem.ID)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	listErr := e.enqueue(ctx, m, report, jobs)
+	close(jobs)
+	wg.Wait()
+
+	if err := m.close(); err != nil {
+		return report, fmt.Errorf("exporting media items: %w", errors.Join(listErr, err))
+	}
+	if listErr != nil {
+		return report, fmt.Errorf("exporting media items: %w", listErr)
+	}
+	return report, nil
+}
+
+// enqueue sends a job for every media item that has not been exported yet.
+// Skipped media items are recorded in report before starting any job.
+func (e *Exporter) enqueue(ctx context.Context, m *manifest, report *Report, jobs chan<- job) error {
+	seq := e.mediaItems.All(ctx)
+	if e.albumID != "" {
+		seq = e.mediaItems.ByAlbum(ctx, e.albumID)
+	}
+
+	// claimed maps the paths assigned in this export to their media items.
+	claimed := make(map[string]string)
+	for mediaItem, err := range seq {
+		if err != nil {
+			return err
+		}
+		if path, ok := m.get(mediaItem.ID); ok && e.exists(path) {
+			report.Skipped = append(report.Skipped, mediaItem.ID)
+			continue
+		}
+		if path, ok := e.findUnrecorded(mediaItem, m, claimed); ok {
+			if err := m.add(mediaItem.ID, path); err != nil {
+				return err
+			}
+			claimed[path] = mediaItem.ID
+			report.Skipped = append(report.Skipped, mediaItem.ID)
+			continue
+		}
+		path := e.pathFor(mediaItem, m, claimed)
+		claimed[path] = mediaItem.ID
+		select {
+		case jobs <- job{mediaItem: mediaItem, path: path}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// pathFor returns a YYYY/MM/filename path for the media item. If the path has been
+// used by another media item, the media item ID is added to the file name.
+func (e *Exporter) pathFor(mediaItem media_items.MediaItem, m *manifest, claimed map[string]string) string {
+	if path, ok := m.get(mediaItem.ID); ok {
+		return path
+	}
+	path, withID := candidatePaths(mediaItem)
+	if _, ok := claimed[path]; ok || e.exists(path) {
+		return withID
+	}
+	return path
+}
+
+// candidatePaths returns the YYYY/MM/filename path of the media item, and the same path
+// with the media item ID added to the file name.
+func candidatePaths(mediaItem media_items.MediaItem) (path, withID string) {
+	dir := "unknown-date"
+	if t := mediaItem.MediaMetadata.CreationTime; !t.IsZero() {
+		dir = filepath.Join(fmt.Sprintf("%04d", t.Year()), fmt.Sprintf("%02d", t.Month()))
+	}
+
+	name := sanitizeFilename(mediaItem.Filename)
+	if name == "" {
+		name = sanitizeFilename(mediaItem.ID)
+	}
+	ext := filepath.Ext(name)
+	return filepath.Join(dir, name), filepath.Join(dir, strings.TrimSuffix(name, ext)+"-"+sanitizeFilename(mediaItem.ID)+ext)
+}
+
+// modTimeTolerance is the precision of the modification times kept by file systems, like FAT.
+const modTimeTolerance = 2 * time.Second
+
+// findUnrecorded returns the path of a media item exported by a previous export that was
+// interrupted before recording it in the manifest. It's one of the candidatePaths, not
+// recorded for another media item, whose modification time is the creation time of the
+// media item, as set by download. Media items without creation time are never found.
+func (e *Exporter) findUnrecorded(mediaItem media_items.MediaItem, m *manifest, claimed map[string]string) (string, bool) {
+	t := mediaItem.MediaMetadata.CreationTime
+	if t.IsZero() {
+		return "", false
+	}
+	path, withID := candidatePaths(mediaItem)
+	for _, path := range []string{path, withID} {
+		if _, ok := m.owner(path); ok {
+			continue
+		}
+		if _, ok := claimed[path]; ok {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(e.dir, path))
+		if err == nil && info.Mode().IsRegular() && info.ModTime().Sub(t).Abs() < modTimeTolerance {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// sanitizeFilename returns a file name without directories.
+func sanitizeFilename(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "." || name == ".." {
+		return ""
+	}
+	return name
+}
+
+// exists returns true if the path, relative to the destination directory, exists.
+func (e *Exporter) exists(path string) bool {
+	_, err := os.Stat(filepath.Join(e.dir, path))
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// download writes the media item bytes to a temporary file, and renames it to
+// the job path once it's complete. The creation time is set as modification time
+// before renaming it, so the file only exists when it's been fully exported.
+func (e *Exporter) download(ctx context.Context, j job) error {
+	r, err := e.mediaItems.Download(ctx, j.mediaItem, media_items.DownloadOptions{OnProgress: e.onProgress})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	path := filepath.Join(e.dir, j.path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if t := j.mediaItem.MediaMetadata.CreationTime; !t.IsZero() {
+		if err := os.Chtimes(f.Name(), t, t); err != nil {
+			_ = os.Remove(f.Name())
+			return err
+		}
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
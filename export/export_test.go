@@ -0,0 +1,292 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/export"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+)
+
+func TestNew(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	testCases := []struct {
+		name          string
+		config        export.Config
+		isErrExpected bool
+	}{
+		{"Should fail without media items service", export.Config{Dir: t.TempDir()}, true},
+		{"Should fail without destination directory", export.Config{MediaItems: m}, true},
+		{"Should fail with negative concurrency", export.Config{MediaItems: m, Dir: t.TempDir(), Concurrency: -1}, true},
+		{"Should success with valid config", export.Config{MediaItems: m, Dir: t.TempDir()}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := export.New(tc.config)
+			assertExpectedError(tc.isErrExpected, err, t)
+		})
+	}
+}
+
+func TestExporter_Export(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	e, err := export.New(export.Config{MediaItems: m, Dir: dir, Concurrency: 8})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	ctx := context.Background()
+
+	t.Run("Should export all media items", func(t *testing.T) {
+		report, err := e.Export(ctx)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if mocks.AvailableMediaItems != len(report.Exported) || len(report.Skipped) != 0 || len(report.Failed) != 0 {
+			t.Errorf("want: %d exported, got: %d exported, %d skipped, %d failed", mocks.AvailableMediaItems, len(report.Exported), len(report.Skipped), len(report.Failed))
+		}
+		assertExportedFile(t, filepath.Join(dir, "2014", "10", "fooFilename-0"), mocks.FakeMediaBytes("fooId-0", "d"), time.Date(2014, time.October, 2, 15, 1, 23, 45123456, time.UTC))
+		assertExportedFile(t, filepath.Join(dir, "2014", "10", "fooFilename-4"), mocks.FakeMediaBytes("fooId-4", "dv"), time.Date(2014, time.October, 6, 15, 1, 23, 45123456, time.UTC))
+		assertExportedFile(t, filepath.Join(dir, "2015", "02", "fooFilename-149"), mocks.FakeMediaBytes("fooId-149", "dv"), time.Date(2015, time.February, 28, 15, 1, 23, 45123456, time.UTC))
+		if _, err := os.Stat(filepath.Join(dir, export.ManifestFile)); err != nil {
+			t.Errorf("want: manifest, got: %s", err)
+		}
+	})
+
+	t.Run("Should skip exported media items", func(t *testing.T) {
+		report, err := e.Export(ctx)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if len(report.Exported) != 0 || mocks.AvailableMediaItems != len(report.Skipped) {
+			t.Errorf("want: %d skipped, got: %d exported, %d skipped", mocks.AvailableMediaItems, len(report.Exported), len(report.Skipped))
+		}
+	})
+
+	t.Run("Should export again removed files", func(t *testing.T) {
+		if err := os.Remove(filepath.Join(dir, "2014", "10", "fooFilename-0")); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		report, err := e.Export(ctx)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if 1 != len(report.Exported) || "fooId-0" != report.Exported[0] {
+			t.Errorf("want: fooId-0 exported, got: %v", report.Exported)
+		}
+		assertExportedFile(t, filepath.Join(dir, "2014", "10", "fooFilename-0"), mocks.FakeMediaBytes("fooId-0", "d"), time.Date(2014, time.October, 2, 15, 1, 23, 45123456, time.UTC))
+	})
+
+	t.Run("Should record exported files missing in the manifest", func(t *testing.T) {
+		// An export interrupted after writing the files, but before recording them.
+		if err := os.Remove(filepath.Join(dir, export.ManifestFile)); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		// A file not written by an export is not recorded.
+		other := filepath.Join(dir, "2014", "10", "fooFilename-1")
+		if err := os.Chtimes(other, time.Now(), time.Now()); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+
+		report, err := e.Export(ctx)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if 1 != len(report.Exported) || "fooId-1" != report.Exported[0] || mocks.AvailableMediaItems-1 != len(report.Skipped) {
+			t.Errorf("want: fooId-1 exported, %d skipped, got: %v exported, %d skipped", mocks.AvailableMediaItems-1, report.Exported, len(report.Skipped))
+		}
+		assertExportedFile(t, filepath.Join(dir, "2014", "10", "fooFilename-1-fooId-1"), mocks.FakeMediaBytes("fooId-1", "d"), time.Date(2014, time.October, 3, 15, 1, 23, 45123456, time.UTC))
+
+		report, err = e.Export(ctx)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if len(report.Exported) != 0 || mocks.AvailableMediaItems != len(report.Skipped) {
+			t.Errorf("want: %d skipped, got: %d exported, %d skipped", mocks.AvailableMediaItems, len(report.Exported), len(report.Skipped))
+		}
+	})
+
+	t.Run("Should report the progress of the downloads", func(t *testing.T) {
+		var mu sync.Mutex
+		completed := make(map[string]bool)
+		e, err := export.New(export.Config{MediaItems: m, Dir: t.TempDir(), OnProgress: func(p media_items.DownloadProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			completed[p.MediaItemID] = p.Received == p.Total
+		}})
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if _, err := e.Export(ctx); err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		for i := range mocks.AvailableMediaItems {
+			if id := fmt.Sprintf("fooId-%d", i); !completed[id] {
+				t.Errorf("want: %s download completed, got: %t", id, completed[id])
+			}
+		}
+	})
+
+	t.Run("Should fail when the context is cancelled", func(t *testing.T) {
+		e, err := export.New(export.Config{MediaItems: m, Dir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := e.Export(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("want: %v, got: %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestExporter_Export_Interrupted(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The export is interrupted when the fourth media item starts to download.
+	interrupted := &interruptingService{Service: m, after: 3, cancel: cancel, manifest: filepath.Join(dir, export.ManifestFile)}
+	e, err := export.New(export.Config{MediaItems: interrupted, Dir: dir, Concurrency: 1})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	if _, err := e.Export(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want: %v, got: %v", context.Canceled, err)
+	}
+	// A crash at this point would have kept the media items exported so far.
+	if 3 != interrupted.recorded {
+		t.Errorf("want: 3 media items in the manifest when interrupted, got: %d", interrupted.recorded)
+	}
+
+	e, err = export.New(export.Config{MediaItems: m, Dir: dir})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	report, err := e.Export(context.Background())
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if 3 != len(report.Skipped) || mocks.AvailableMediaItems-3 != len(report.Exported) {
+		t.Errorf("want: 3 skipped, got: %d exported, %d skipped", len(report.Exported), len(report.Skipped))
+	}
+	duplicates, err := filepath.Glob(filepath.Join(dir, "*", "*", "*-fooId-*"))
+	if err != nil || len(duplicates) != 0 {
+		t.Errorf("want: no duplicates, got: %v", duplicates)
+	}
+}
+
+// interruptingService cancels the export when a download starts after the given number of downloads,
+// counting the media items recorded in the manifest at that moment.
+type interruptingService struct {
+	*media_items.Service
+	after     int
+	downloads int
+	cancel    context.CancelFunc
+	manifest  string
+	recorded  int
+}
+
+func (s *interruptingService) Download(ctx context.Context, mediaItem media_items.MediaItem, options media_items.DownloadOptions) (io.ReadCloser, error) {
+	if s.downloads == s.after {
+		b, _ := os.ReadFile(s.manifest)
+		s.recorded = bytes.Count(b, []byte(`"id"`))
+		s.cancel()
+		return nil, ctx.Err()
+	}
+	s.downloads++
+	return s.Service.Download(ctx, mediaItem, options)
+}
+
+func TestExporter_Export_Album(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	a, err := albums.New(albums.Config{Client: http.DefaultClient, BaseURL: srv.URL()})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	ctx := context.Background()
+	if err := a.RemoveMediaItems(ctx, "fooId-5", []string{"fooId-0", "fooId-1"}); err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	if err := a.AddMediaItems(ctx, "fooId-5", []string{"non-existent"}); err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	dir := t.TempDir()
+	e, err := export.New(export.Config{MediaItems: m, Dir: dir, AlbumID: "fooId-5"})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	report, err := e.Export(ctx)
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if mocks.AvailableMediaItems-2 != len(report.Exported) {
+		t.Errorf("want: %d, got: %d", mocks.AvailableMediaItems-2, len(report.Exported))
+	}
+	if _, ok := report.Failed["non-existent"]; !ok || 1 != len(report.Failed) {
+		t.Errorf("want: non-existent failed, got: %v", report.Failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2014", "10", "fooFilename-0")); err == nil {
+		t.Errorf("want: media item not in the album to be skipped, got: exported")
+	}
+}
+
+func newMediaItemsService(t *testing.T) (*media_items.Service, *mocks.MockedGooglePhotosService) {
+	t.Helper()
+	srv := mocks.NewMockedGooglePhotosService()
+	m, err := media_items.New(media_items.Config{Client: http.DefaultClient, BaseURL: srv.URL()})
+	if err != nil {
+		srv.Close()
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	return m, srv
+}
+
+func assertExportedFile(t *testing.T, path string, want []byte, wantModTime time.Time) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("want: %d bytes, got: %d bytes not matching", len(want), len(got))
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if !wantModTime.Equal(fi.ModTime()) {
+		t.Errorf("want: %s, got: %s", wantModTime, fi.ModTime())
+	}
+}
+
+func assertExpectedError(isErrExpected bool, err error, t *testing.T) {
+	if isErrExpected && err == nil {
+		t.Fatalf("error was expected, but not produced")
+	}
+	if !isErrExpected && err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/jsonl"
+)
+
+// ManifestFile is the name of the manifest, written in the destination directory.
+// It keeps the media items that have been exported, so following exports are incremental.
+const ManifestFile = ".gphotos-export.jsonl"
+
+// manifest maps the ID of every exported media item to its path, relative to the destination directory.
+// Every exported media item is appended to the manifest file as soon as it's exported,
+// so an interrupted export keeps the media items exported so far. It's safe for concurrent use.
+type manifest struct {
+	log *jsonl.Log[manifestEntry]
+
+	mu    sync.Mutex
+	items map[string]string
+	paths map[string]string // the media item ID of every path.
+}
+
+// manifestEntry is a line of the manifest file.
+type manifestEntry struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+}
+
+// openManifest opens, or creates, the manifest in dir. The caller must close it.
+func openManifest(dir string) (*manifest, error) {
+	m := &manifest{items: make(map[string]string), paths: make(map[string]string)}
+	log, err := jsonl.Open(filepath.Join(dir, ManifestFile), func(e manifestEntry) {
+		m.set(e.ID, e.Path)
+	}, m.entries)
+	if err != nil {
+		return nil, err
+	}
+	m.log = log
+	return m, nil
+}
+
+// get returns the path of an exported media item.
+func (m *manifest) get(mediaItemID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path, ok := m.items[mediaItemID]
+	return path, ok
+}
+
+// owner returns the ID of the media item exported to path.
+func (m *manifest) owner(path string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, ok := m.paths[path]
+	return id, ok
+}
+
+// add records an exported media item, appending it to the manifest file.
+func (m *manifest) add(mediaItemID, path string) error {
+	if err := m.log.Append(manifestEntry{ID: mediaItemID, Path: path}); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(mediaItemID, path)
+	return nil
+}
+
+// set records the path of a media item in memory. m.mu must be held, unless m is being loaded.
+func (m *manifest) set(mediaItemID, path string) {
+	if old, ok := m.items[mediaItemID]; ok {
+		delete(m.paths, old)
+	}
+	m.items[mediaItemID] = path
+	m.paths[path] = mediaItemID
+}
+
+// entries returns the latest path of every media item, to compact the manifest file.
+func (m *manifest) entries() []manifestEntry {
+	entries := make([]manifestEntry, 0, len(m.items))
+	for id, path := range m.items {
+		entries = append(entries, manifestEntry{ID: id, Path: path})
+	}
+	return entries
+}
+
+// close closes the manifest file.
+func (m *manifest) close() error {
+	return m.log.Close()
+}
@@ -5,6 +5,7 @@ go 1.23.0
 toolchain go1.24.0
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/gphotosuploader/googlemirror v0.5.0
 	github.com/hashicorp/go-retryablehttp v0.7.8
@@ -0,0 +1,3 @@
+// Package filelock implements advisory locks on files, to share them between processes.
+// Locks are supported on Unix and Windows systems. On the rest of them, locking does nothing.
+package filelock
@@ -0,0 +1,17 @@
+//go:build !unix && !windows
+
+package filelock
+
+import (
+	"os"
+)
+
+// Lock does nothing: file locks are only supported on Unix and Windows systems.
+func Lock(f *os.File, exclusive bool) error {
+	return nil
+}
+
+// Unlock does nothing: file locks are only supported on Unix and Windows systems.
+func Unlock(f *os.File) error {
+	return nil
+}
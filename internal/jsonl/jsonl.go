@@ -0,0 +1,194 @@
+// Package jsonl implements append-only files of JSON lines, used to persist state that
+// must survive interruptions, like the media items already exported or uploaded.
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/filelock"
+)
+
+// Log is an append-only file of JSON values of type T, one per line. Every value is
+// written as soon as it's appended, so an interrupted program keeps the values appended
+// so far. It's safe for concurrent use by multiple goroutines and, where files can be
+// locked (see filelock), by multiple processes: the file is locked, using a ".lock" file
+// next to it, while it's read or written.
+type Log[T any] struct {
+	name string
+
+	mu   sync.Mutex
+	file *os.File // nil when closed.
+	lock *os.File
+}
+
+// Open opens, or creates, the log in the named file, calling load with every value in it,
+// in order. The caller must close it.
+//
+// Then, if compact is not nil and it returns fewer values than loaded, the file is replaced
+// by them, so overwritten or deleted values don't make it grow forever. The file is kept as
+// it is when it can't be replaced, like on Windows while other processes have it open.
+func Open[T any](name string, load func(T), compact func() []T) (*Log[T], error) {
+	lock, err := os.OpenFile(name+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", name, err)
+	}
+	l := &Log[T]{name: name, lock: lock}
+	if err := l.open(load, compact); err != nil {
+		_ = lock.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// open loads and compacts the file, and opens it for appending, with the file locked.
+func (l *Log[T]) open(load func(T), compact func() []T) error {
+	if err := filelock.Lock(l.lock, true); err != nil {
+		return fmt.Errorf("locking %s: %w", l.name, err)
+	}
+	defer func() { _ = filelock.Unlock(l.lock) }()
+
+	loaded, err := l.load(load)
+	if err != nil {
+		return err
+	}
+	if compact != nil {
+		if values := compact(); len(values) < loaded {
+			if err := l.replace(values); err != nil {
+				return err
+			}
+		}
+	}
+	l.file, err = os.OpenFile(l.name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", l.name, err)
+	}
+	return nil
+}
+
+// load calls fn with every value in the file, and returns how many of them there are.
+func (l *Log[T]) load(fn func(T)) (int, error) {
+	f, err := os.Open(l.name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("opening %s: %w", l.name, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var loaded int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var v T
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			// A partial line is left by a write interrupted by a crash. Ignore it.
+			continue
+		}
+		fn(v)
+		loaded++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("reading %s: %w", l.name, err)
+	}
+	return loaded, nil
+}
+
+// replace writes values to a temporary file, and renames it to the log file.
+func (l *Log[T]) replace(values []T) error {
+	f, err := os.CreateTemp(filepath.Dir(l.name), filepath.Base(l.name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("compacting %s: %w", l.name, err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("compacting %s: %w", l.name, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("compacting %s: %w", l.name, err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("compacting %s: %w", l.name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("compacting %s: %w", l.name, err)
+	}
+	// The file is kept as it is if it can't be replaced.
+	_ = os.Rename(f.Name(), l.name)
+	return nil
+}
+
+// Append writes v at the end of the log.
+func (l *Log[T]) Append(v T) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("writing %s: log is closed", l.name)
+	}
+	if err := filelock.Lock(l.lock, true); err != nil {
+		return fmt.Errorf("locking %s: %w", l.name, err)
+	}
+	defer func() { _ = filelock.Unlock(l.lock) }()
+
+	if err := l.reopenIfReplaced(); err != nil {
+		return err
+	}
+	// A previous interrupted write could have left a partial line, so every value starts in a new line.
+	if _, err := l.file.Write(append(append([]byte{'\n'}, b...), '\n')); err != nil {
+		return fmt.Errorf("writing %s: %w", l.name, err)
+	}
+	return nil
+}
+
+// reopenIfReplaced opens the log file again if another process has compacted it,
+// so values are not appended to the replaced file.
+func (l *Log[T]) reopenIfReplaced() error {
+	current, err := l.file.Stat()
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", l.name, err)
+	}
+	if latest, err := os.Stat(l.name); err == nil && os.SameFile(current, latest) {
+		return nil
+	}
+	f, err := os.OpenFile(l.name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", l.name, err)
+	}
+	_ = l.file.Close()
+	l.file = f
+	return nil
+}
+
+// Close closes the log file. Appending to a closed log fails.
+func (l *Log[T]) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := errors.Join(l.file.Close(), l.lock.Close())
+	l.file = nil
+	if err != nil {
+		return fmt.Errorf("closing %s: %w", l.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package jsonl_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/jsonl"
+)
+
+type entry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func openLog(t *testing.T, name string) (*jsonl.Log[entry], []entry) {
+	t.Helper()
+	var loaded []entry
+	l, err := jsonl.Open(name, func(e entry) { loaded = append(loaded, e) }, nil)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	return l, loaded
+}
+
+func TestLog(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log.jsonl")
+
+	t.Run("Should create an empty log", func(t *testing.T) {
+		l, loaded := openLog(t, name)
+		defer l.Close()
+		if len(loaded) != 0 {
+			t.Errorf("want: no values, got: %v", loaded)
+		}
+	})
+
+	t.Run("Should load appended values in order", func(t *testing.T) {
+		l, _ := openLog(t, name)
+		want := []entry{{"foo", "1"}, {"bar", "2"}, {"foo", "3"}}
+		for _, e := range want {
+			if err := l.Append(e); err != nil {
+				t.Fatalf("error was not expected at this point: %s", err)
+			}
+		}
+		if err := l.Close(); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+
+		l, loaded := openLog(t, name)
+		defer l.Close()
+		if !slices.Equal(want, loaded) {
+			t.Errorf("want: %v, got: %v", want, loaded)
+		}
+	})
+
+	t.Run("Should ignore partial lines", func(t *testing.T) {
+		// A write interrupted by a crash.
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if _, err := f.WriteString(`{"key":"ba`); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		_ = f.Close()
+
+		l, _ := openLog(t, name)
+		if err := l.Append(entry{"baz", "4"}); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		_ = l.Close()
+
+		l, loaded := openLog(t, name)
+		defer l.Close()
+		if want := (entry{"baz", "4"}); len(loaded) != 4 || want != loaded[3] {
+			t.Errorf("want: %v last, got: %v", want, loaded)
+		}
+	})
+
+	t.Run("Should compact the log when it's opened", func(t *testing.T) {
+		// Keeps the latest value of every key.
+		latest := make(map[string]string)
+		var keys []string
+		l, err := jsonl.Open(name, func(e entry) {
+			if _, ok := latest[e.Key]; !ok {
+				keys = append(keys, e.Key)
+			}
+			latest[e.Key] = e.Value
+		}, func() []entry {
+			var entries []entry
+			for _, k := range keys {
+				entries = append(entries, entry{k, latest[k]})
+			}
+			return entries
+		})
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		_ = l.Close()
+
+		l, loaded := openLog(t, name)
+		defer l.Close()
+		want := []entry{{"foo", "3"}, {"bar", "2"}, {"baz", "4"}}
+		if !slices.Equal(want, loaded) {
+			t.Errorf("want: %v, got: %v", want, loaded)
+		}
+	})
+
+	t.Run("Should append to a log compacted by another one", func(t *testing.T) {
+		l, _ := openLog(t, name)
+		defer l.Close()
+
+		compacted, err := jsonl.Open(name, func(entry) {}, func() []entry { return nil })
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		defer compacted.Close()
+
+		if err := l.Append(entry{"foo", "5"}); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		reopened, loaded := openLog(t, name)
+		defer reopened.Close()
+		if want := []entry{{"foo", "5"}}; !slices.Equal(want, loaded) {
+			t.Errorf("want: %v, got: %v", want, loaded)
+		}
+	})
+
+	t.Run("Should fail when appending to a closed log", func(t *testing.T) {
+		l, _ := openLog(t, name)
+		_ = l.Close()
+		if err := l.Append(entry{"foo", "5"}); err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+}
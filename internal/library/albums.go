@@ -0,0 +1,113 @@
+package library
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// Albums implements the albums calls missing in `photoslibrary.AlbumsService`.
+type Albums struct {
+	s *Service
+}
+
+// AlbumPatch holds the album fields that can be updated.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/albums/patch.
+type AlbumPatch struct {
+	// Title: Name of the album displayed to the user.
+	Title string `json:"title,omitempty"`
+
+	// CoverPhotoMediaItemId: Identifier for the media item associated with the cover photo.
+	CoverPhotoMediaItemId string `json:"coverPhotoMediaItemId,omitempty"`
+}
+
+// AlbumsPatchCall updates an album.
+type AlbumsPatchCall struct {
+	call
+}
+
+// Patch updates the album with the specified albumId.
+// Only the fields listed in the update mask are updated.
+func (r *Albums) Patch(albumId string, album *AlbumPatch) *AlbumsPatchCall {
+	return &AlbumsPatchCall{
+		call: newCall(r.s, http.MethodPatch, "v1/albums/{+albumId}", map[string]string{"albumId": albumId}, album),
+	}
+}
+
+// UpdateMask sets the comma-separated list of fields to update.
+// Valid fields are "title" and "coverPhotoMediaItemId".
+func (c *AlbumsPatchCall) UpdateMask(updateMask string) *AlbumsPatchCall {
+	c.urlParams.Set("updateMask", updateMask)
+	return c
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *AlbumsPatchCall) Context(ctx context.Context) *AlbumsPatchCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.albums.patch" call.
+func (c *AlbumsPatchCall) Do() (*photoslibrary.Album, error) {
+	var album photoslibrary.Album
+	if err := c.do(&album); err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// AlbumBatchRemoveMediaItemsRequest: Request to remove a list of media items from an album.
+type AlbumBatchRemoveMediaItemsRequest struct {
+	// MediaItemIds: Identifiers of the media items to be removed.
+	MediaItemIds []string `json:"mediaItemIds,omitempty"`
+}
+
+// AlbumBatchRemoveMediaItemsCall removes media items from an album.
+type AlbumBatchRemoveMediaItemsCall struct {
+	call
+}
+
+// BatchRemoveMediaItems removes one or more media items from the album with the specified albumId.
+// The media items themselves are not deleted from the user's library.
+func (r *Albums) BatchRemoveMediaItems(albumId string, request *AlbumBatchRemoveMediaItemsRequest) *AlbumBatchRemoveMediaItemsCall {
+	return &AlbumBatchRemoveMediaItemsCall{
+		call: newCall(r.s, http.MethodPost, "v1/albums/{+albumId}:batchRemoveMediaItems", map[string]string{"albumId": albumId}, request),
+	}
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *AlbumBatchRemoveMediaItemsCall) Context(ctx context.Context) *AlbumBatchRemoveMediaItemsCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.albums.batchRemoveMediaItems" call.
+func (c *AlbumBatchRemoveMediaItemsCall) Do() error {
+	return c.do(nil)
+}
+
+// AlbumsUnshareCall marks an album as not shared.
+type AlbumsUnshareCall struct {
+	call
+}
+
+// Unshare marks the album with the specified albumId as private to the user.
+// This action can only be performed on albums created by the app.
+func (r *Albums) Unshare(albumId string) *AlbumsUnshareCall {
+	return &AlbumsUnshareCall{
+		call: newCall(r.s, http.MethodPost, "v1/albums/{+albumId}:unshare", map[string]string{"albumId": albumId}, struct{}{}),
+	}
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *AlbumsUnshareCall) Context(ctx context.Context) *AlbumsUnshareCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.albums.unshare" call.
+func (c *AlbumsUnshareCall) Do() error {
+	return c.do(nil)
+}
@@ -0,0 +1,123 @@
+// Package library implements the Google Photos Library API methods that are not
+// provided by `gphotosuploader/googlemirror/api/photoslibrary`.
+//
+// Calls follow the same shape as the ones in `photoslibrary` (a builder with
+// Context and Do methods), and they return `photoslibrary` types whenever
+// the API resource already exists there.
+package library
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/api/googleapi"
+)
+
+const basePath = "https://photoslibrary.googleapis.com/"
+
+// Service talks to the Google Photos Library API.
+type Service struct {
+	client    *http.Client
+	BasePath  string // API endpoint base URL
+	UserAgent string // optional additional User-Agent fragment
+
+	Albums *Albums
+
+	MediaItems *MediaItems
+
+	SharedAlbums *SharedAlbums
+}
+
+// New returns a Google Photos Library API service using the given HTTP client.
+func New(client *http.Client) (*Service, error) {
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	s := &Service{client: client, BasePath: basePath}
+	s.Albums = &Albums{s: s}
+	s.MediaItems = &MediaItems{s: s}
+	s.SharedAlbums = &SharedAlbums{s: s}
+	return s, nil
+}
+
+func (s *Service) userAgent() string {
+	if s.UserAgent == "" {
+		return googleapi.UserAgent
+	}
+	return googleapi.UserAgent + " " + s.UserAgent
+}
+
+// call holds the parts shared by every API call.
+type call struct {
+	s          *Service
+	method     string
+	path       string
+	pathParams map[string]string
+	urlParams  url.Values
+	body       interface{}
+	ctx        context.Context
+}
+
+func newCall(s *Service, method, path string, pathParams map[string]string, body interface{}) call {
+	return call{
+		s:          s,
+		method:     method,
+		path:       path,
+		pathParams: pathParams,
+		urlParams:  make(url.Values),
+		body:       body,
+	}
+}
+
+// do executes the call and decodes the JSON response into result, if it's not nil.
+//
+// Any non-2xx status code is an error of type *googleapi.Error.
+func (c *call) do(result interface{}) error {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var body io.Reader
+	if c.body != nil {
+		b, err := json.Marshal(c.body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	urls := googleapi.ResolveRelative(c.s.BasePath, c.path)
+	if len(c.urlParams) > 0 {
+		urls += "?" + c.urlParams.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, c.method, urls, body)
+	if err != nil {
+		return err
+	}
+	googleapi.Expand(req.URL, c.pathParams)
+	req.Header.Set("User-Agent", c.s.userAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer googleapi.CloseBody(res)
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
@@ -0,0 +1,321 @@
+package library
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// MediaItems implements the media items calls missing in `photoslibrary.MediaItemsService`.
+type MediaItems struct {
+	s *Service
+}
+
+// FeatureFilter: Filters the media items based on their features.
+type FeatureFilter struct {
+	// IncludedFeatures: The set of features to be included in the media item search results.
+	// Possible values: "NONE", "FAVORITES".
+	IncludedFeatures []string `json:"includedFeatures,omitempty"`
+}
+
+// Filters: Filters that can be applied to a media item search.
+// If multiple filter options are specified, they're treated as AND with each other.
+type Filters struct {
+	// ContentFilter: Filters the media items based on their content.
+	ContentFilter *photoslibrary.ContentFilter `json:"contentFilter,omitempty"`
+
+	// DateFilter: Filters the media items based on their creation date.
+	DateFilter *photoslibrary.DateFilter `json:"dateFilter,omitempty"`
+
+	// FeatureFilter: Filters the media items based on their features.
+	FeatureFilter *FeatureFilter `json:"featureFilter,omitempty"`
+
+	// MediaTypeFilter: Filters the media items based on the type of media.
+	MediaTypeFilter *photoslibrary.MediaTypeFilter `json:"mediaTypeFilter,omitempty"`
+
+	// IncludeArchivedMedia: If set, the results include media items that the user has archived.
+	IncludeArchivedMedia bool `json:"includeArchivedMedia,omitempty"`
+
+	// ExcludeNonAppCreatedData: If set, the results exclude media items that were not created by this app.
+	ExcludeNonAppCreatedData bool `json:"excludeNonAppCreatedData,omitempty"`
+}
+
+// SearchMediaItemsRequest: Request to search for media items in a user's library.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search.
+type SearchMediaItemsRequest struct {
+	// AlbumId: Identifier of an album. If populated, lists all media items in the specified album.
+	// Can't be set in conjunction with any filters.
+	AlbumId string `json:"albumId,omitempty"`
+
+	// PageSize: Maximum number of media items to return in the response.
+	PageSize int64 `json:"pageSize,omitempty"`
+
+	// PageToken: A continuation token to get the next page of the results.
+	PageToken string `json:"pageToken,omitempty"`
+
+	// Filters: Filters to apply to the request. Can't be set in conjunction with an AlbumId.
+	Filters *Filters `json:"filters,omitempty"`
+
+	// OrderBy: An optional field to specify the sort order of the search results.
+	// It only works when a DateFilter is used.
+	// Possible values: "MediaMetadata.creation_time" and "MediaMetadata.creation_time desc".
+	OrderBy string `json:"orderBy,omitempty"`
+}
+
+// MediaItemsSearchCall searches for media items.
+type MediaItemsSearchCall struct {
+	call
+	request *SearchMediaItemsRequest
+}
+
+// Search searches for media items in a user's Google Photos library.
+func (r *MediaItems) Search(request *SearchMediaItemsRequest) *MediaItemsSearchCall {
+	return &MediaItemsSearchCall{
+		call:    newCall(r.s, http.MethodPost, "v1/mediaItems:search", nil, request),
+		request: request,
+	}
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *MediaItemsSearchCall) Context(ctx context.Context) *MediaItemsSearchCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.mediaItems.search" call.
+func (c *MediaItemsSearchCall) Do() (*photoslibrary.SearchMediaItemsResponse, error) {
+	var res photoslibrary.SearchMediaItemsResponse
+	if err := c.do(&res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Pages invokes f for each page of results.
+// A non-nil error returned from f will halt the iteration.
+// The provided context supersedes any context provided to the Context method.
+func (c *MediaItemsSearchCall) Pages(ctx context.Context, f func(*photoslibrary.SearchMediaItemsResponse) error) error {
+	c.ctx = ctx
+	pageToken := c.request.PageToken
+	defer func() { c.request.PageToken = pageToken }() // reset paging to original point
+	for {
+		x, err := c.Do()
+		if err != nil {
+			return err
+		}
+		if err := f(x); err != nil {
+			return err
+		}
+		if x.NextPageToken == "" {
+			return nil
+		}
+		c.request.PageToken = x.NextPageToken
+	}
+}
+
+// SimpleMediaItem: A simple media item to be created in Google Photos via an upload token.
+type SimpleMediaItem struct {
+	// FileName: File name with extension of the media item. This is shown to the user in
+	// Google Photos. The file name specified during the byte upload process is ignored
+	// if this field is set.
+	FileName string `json:"fileName,omitempty"`
+
+	// UploadToken: Token identifying the media bytes that have been uploaded to Google.
+	UploadToken string `json:"uploadToken,omitempty"`
+}
+
+// NewMediaItem: New media item that's created in a user's Google Photos account.
+type NewMediaItem struct {
+	// Description: Description of the media item. This is shown to the user in the item's
+	// info section in the Google Photos app. Must be shorter than 1000 characters.
+	Description string `json:"description,omitempty"`
+
+	// SimpleMediaItem: A new media item that has been uploaded via the included uploadToken.
+	SimpleMediaItem *SimpleMediaItem `json:"simpleMediaItem,omitempty"`
+}
+
+// BatchCreateMediaItemsRequest: Request to create one or more media items in a user's Google
+// Photos library.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/batchCreate.
+type BatchCreateMediaItemsRequest struct {
+	// AlbumId: Identifier of the album where the media items are added.
+	AlbumId string `json:"albumId,omitempty"`
+
+	// AlbumPosition: Position in the album where the media items are added.
+	// If not specified, the media items are added to the end of the album.
+	AlbumPosition *photoslibrary.AlbumPosition `json:"albumPosition,omitempty"`
+
+	// NewMediaItems: List of media items to be created. Maximum 50 media items per call.
+	NewMediaItems []*NewMediaItem `json:"newMediaItems,omitempty"`
+}
+
+// MediaItemsBatchCreateCall creates media items.
+type MediaItemsBatchCreateCall struct {
+	call
+}
+
+// BatchCreate creates one or more media items in a user's Google Photos library.
+func (r *MediaItems) BatchCreate(request *BatchCreateMediaItemsRequest) *MediaItemsBatchCreateCall {
+	return &MediaItemsBatchCreateCall{
+		call: newCall(r.s, http.MethodPost, "v1/mediaItems:batchCreate", nil, request),
+	}
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *MediaItemsBatchCreateCall) Context(ctx context.Context) *MediaItemsBatchCreateCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.mediaItems.batchCreate" call.
+func (c *MediaItemsBatchCreateCall) Do() (*photoslibrary.BatchCreateMediaItemsResponse, error) {
+	var res photoslibrary.BatchCreateMediaItemsResponse
+	if err := c.do(&res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// MediaItemResult: Result of retrieving a media item.
+type MediaItemResult struct {
+	// MediaItem: Media item retrieved from the user's library. It's populated if no errors occurred.
+	MediaItem *photoslibrary.MediaItem `json:"mediaItem,omitempty"`
+
+	// Status: If an error occurred while accessing this media item, this field is populated
+	// with information related to the error.
+	Status *photoslibrary.Status `json:"status,omitempty"`
+}
+
+// BatchGetMediaItemsResponse: Response to retrieve a list of media items.
+type BatchGetMediaItemsResponse struct {
+	// MediaItemResults: List of media items retrieved, in the same order as the requested IDs.
+	MediaItemResults []*MediaItemResult `json:"mediaItemResults,omitempty"`
+}
+
+// MediaItemsBatchGetCall retrieves many media items.
+type MediaItemsBatchGetCall struct {
+	call
+}
+
+// BatchGet returns the list of media items for the specified media item identifiers.
+// Items are returned in the same order as the supplied identifiers.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/batchGet.
+func (r *MediaItems) BatchGet(mediaItemIds []string) *MediaItemsBatchGetCall {
+	c := &MediaItemsBatchGetCall{
+		call: newCall(r.s, http.MethodGet, "v1/mediaItems:batchGet", nil, nil),
+	}
+	c.urlParams["mediaItemIds"] = mediaItemIds
+	return c
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *MediaItemsBatchGetCall) Context(ctx context.Context) *MediaItemsBatchGetCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.mediaItems.batchGet" call.
+func (c *MediaItemsBatchGetCall) Do() (*BatchGetMediaItemsResponse, error) {
+	var res BatchGetMediaItemsResponse
+	if err := c.do(&res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// MediaItemPatch: The media item fields that can be updated.
+type MediaItemPatch struct {
+	// Description: Description of the media item. An empty description clears it.
+	Description string `json:"description"`
+}
+
+// MediaItemsPatchCall updates a media item.
+type MediaItemsPatchCall struct {
+	call
+}
+
+// Patch updates the media item with the specified mediaItemId.
+// Only the fields listed in the update mask are updated.
+func (r *MediaItems) Patch(mediaItemId string, mediaItem *MediaItemPatch) *MediaItemsPatchCall {
+	return &MediaItemsPatchCall{
+		call: newCall(r.s, http.MethodPatch, "v1/mediaItems/{+mediaItemId}", map[string]string{"mediaItemId": mediaItemId}, mediaItem),
+	}
+}
+
+// UpdateMask sets the comma-separated list of fields to update.
+// The only valid field is "description".
+func (c *MediaItemsPatchCall) UpdateMask(updateMask string) *MediaItemsPatchCall {
+	c.urlParams.Set("updateMask", updateMask)
+	return c
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *MediaItemsPatchCall) Context(ctx context.Context) *MediaItemsPatchCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.mediaItems.patch" call.
+func (c *MediaItemsPatchCall) Do() (*photoslibrary.MediaItem, error) {
+	var mediaItem photoslibrary.MediaItem
+	if err := c.do(&mediaItem); err != nil {
+		return nil, err
+	}
+	return &mediaItem, nil
+}
+
+// ListMediaItemsResponse: List of all media items from the user's Google Photos library.
+type ListMediaItemsResponse struct {
+	// MediaItems: List of media items in the user's library.
+	MediaItems []*photoslibrary.MediaItem `json:"mediaItems,omitempty"`
+
+	// NextPageToken: Token to use to get the next set of media items.
+	// Its presence is the only reliable indicator of more media items being available.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// MediaItemsListCall lists media items.
+type MediaItemsListCall struct {
+	call
+}
+
+// List lists all media items from a user's Google Photos library.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/list.
+func (r *MediaItems) List() *MediaItemsListCall {
+	return &MediaItemsListCall{
+		call: newCall(r.s, http.MethodGet, "v1/mediaItems", nil, nil),
+	}
+}
+
+// PageSize sets the maximum number of media items to return in the response, up to 100.
+func (c *MediaItemsListCall) PageSize(pageSize int64) *MediaItemsListCall {
+	c.urlParams.Set("pageSize", strconv.FormatInt(pageSize, 10))
+	return c
+}
+
+// PageToken sets the continuation token to get the next page of the results.
+func (c *MediaItemsListCall) PageToken(pageToken string) *MediaItemsListCall {
+	c.urlParams.Set("pageToken", pageToken)
+	return c
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *MediaItemsListCall) Context(ctx context.Context) *MediaItemsListCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.mediaItems.list" call.
+func (c *MediaItemsListCall) Do() (*ListMediaItemsResponse, error) {
+	var res ListMediaItemsResponse
+	if err := c.do(&res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,70 @@
+package library
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// SharedAlbums implements the shared albums calls missing in `photoslibrary.SharedAlbumsService`.
+type SharedAlbums struct {
+	s *Service
+}
+
+// SharedAlbumsGetCall returns a shared album.
+type SharedAlbumsGetCall struct {
+	call
+}
+
+// Get returns the album based on the specified shareToken.
+func (r *SharedAlbums) Get(shareToken string) *SharedAlbumsGetCall {
+	return &SharedAlbumsGetCall{
+		call: newCall(r.s, http.MethodGet, "v1/sharedAlbums/{+shareToken}", map[string]string{"shareToken": shareToken}, nil),
+	}
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *SharedAlbumsGetCall) Context(ctx context.Context) *SharedAlbumsGetCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.sharedAlbums.get" call.
+func (c *SharedAlbumsGetCall) Do() (*photoslibrary.Album, error) {
+	var album photoslibrary.Album
+	if err := c.do(&album); err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// LeaveSharedAlbumRequest: Request to leave a shared album on behalf of the user.
+type LeaveSharedAlbumRequest struct {
+	// ShareToken: Token to leave the shared album on behalf of the user.
+	ShareToken string `json:"shareToken,omitempty"`
+}
+
+// SharedAlbumsLeaveCall leaves a shared album.
+type SharedAlbumsLeaveCall struct {
+	call
+}
+
+// Leave leaves a previously-joined shared album on behalf of the user.
+// The user must not own this album.
+func (r *SharedAlbums) Leave(request *LeaveSharedAlbumRequest) *SharedAlbumsLeaveCall {
+	return &SharedAlbumsLeaveCall{
+		call: newCall(r.s, http.MethodPost, "v1/sharedAlbums:leave", nil, request),
+	}
+}
+
+// Context sets the context to be used in this call's Do method.
+func (c *SharedAlbumsLeaveCall) Context(ctx context.Context) *SharedAlbumsLeaveCall {
+	c.ctx = ctx
+	return c
+}
+
+// Do executes the "photoslibrary.sharedAlbums.leave" call.
+func (c *SharedAlbumsLeaveCall) Do() error {
+	return c.do(nil)
+}
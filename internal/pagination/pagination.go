@@ -0,0 +1,47 @@
+// Package pagination walks the paginated responses of the Google Photos Library API.
+package pagination
+
+import (
+	"context"
+	"iter"
+)
+
+// FetchFunc retrieves the page with the given page token, returning its items
+// and the token of the next page. An empty next page token means there are no more pages.
+type FetchFunc[T any] func(ctx context.Context, pageToken string) (items []T, nextPageToken string, err error)
+
+// Seq returns an iterator over the items of every page, starting at startPageToken.
+// Pages are retrieved when they are needed, so only one page is kept in memory.
+//
+// The iteration stops after yielding the first error, which is ctx.Err() if
+// the context has been cancelled.
+func Seq[T any](ctx context.Context, startPageToken string, fetch FetchFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		pageToken := startPageToken
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			items, nextPageToken, err := fetch(ctx, pageToken)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if err := ctx.Err(); err != nil {
+					yield(zero, err)
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if nextPageToken == "" {
+				return
+			}
+			pageToken = nextPageToken
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package media_items
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// baseURLLifetime is the time a BaseURL remains active after retrieving the media item.
+//
+// See: https://developers.google.com/photos/library/guides/access-media-items#base-urls.
+const baseURLLifetime = 60 * time.Minute
+
+// baseURLRefreshAge is the age of a BaseURL when it's refreshed before downloading, ahead
+// of its expiration, so it doesn't expire while the download is requested.
+const baseURLRefreshAge = baseURLLifetime - 5*time.Minute
+
+// OriginalKind chooses the original bytes downloaded, see DownloadOptions.Original.
+type OriginalKind string
+
+const (
+	// OriginalAuto downloads the video bytes (=dv) of videos, and the image bytes (=d)
+	// of the rest of the media items. It's the default value.
+	OriginalAuto OriginalKind = ""
+
+	// OriginalImage downloads the image bytes with their metadata (=d). For videos,
+	// it's the image of their thumbnail.
+	OriginalImage OriginalKind = "d"
+
+	// OriginalVideo downloads the video bytes (=dv). It requires a video media item.
+	OriginalVideo OriginalKind = "dv"
+)
+
+// DownloadOptions set the options for the Download call.
+//
+// See: https://developers.google.com/photos/library/guides/access-media-items#base-urls.
+type DownloadOptions struct {
+	// MaxWidth and MaxHeight scale the photo, or the thumbnail of a video, keeping its
+	// aspect ratio, to fit in the given dimensions. When both are zero, the original
+	// bytes are downloaded, as chosen by Original.
+	MaxWidth  int64
+	MaxHeight int64
+
+	// Original chooses the original bytes downloaded when MaxWidth and MaxHeight are zero.
+	// Defaults to OriginalAuto, which uses the media type of the media item.
+	Original OriginalKind
+
+	// Crop crops the photo to the exact MaxWidth and MaxHeight dimensions.
+	// It requires both of them.
+	Crop bool
+
+	// Offset is the number of bytes already downloaded. If set, the download is
+	// resumed at that offset using a Range request. Partial responses starting
+	// at another offset are rejected.
+	Offset int64
+
+	// OnProgress, if set, is called with the progress of the download, as the returned
+	// reader is read. It's called from the goroutine reading it, so it should return quickly.
+	OnProgress DownloadProgressFunc
+}
+
+// DownloadProgress is the progress of a download.
+type DownloadProgress struct {
+	// MediaItemID is the ID of the downloaded media item.
+	MediaItemID string
+
+	// Received is the number of bytes received, including the ones before Offset.
+	Received int64
+
+	// Total is the size of the download, or -1 if the server didn't send it.
+	Total int64
+
+	// Offset is where the download was resumed from. It's 0 for downloads started from scratch.
+	Offset int64
+
+	// Throughput is the average number of bytes per second received since the download started.
+	Throughput float64
+}
+
+// DownloadProgressFunc is called with the progress of a download.
+type DownloadProgressFunc func(DownloadProgress)
+
+// downloadProgressInterval is the minimum time between two progress reports of the same download.
+// The first report and the end of the download are always reported.
+const downloadProgressInterval = 200 * time.Millisecond
+
+func (o DownloadOptions) validate() error {
+	if o.MaxWidth < 0 || o.MaxHeight < 0 {
+		return errors.New("invalid dimensions: they must be positive")
+	}
+	if o.Crop && (o.MaxWidth == 0 || o.MaxHeight == 0) {
+		return errors.New("crop requires both max width and max height")
+	}
+	if o.Offset < 0 {
+		return fmt.Errorf("invalid offset %d", o.Offset)
+	}
+	switch o.Original {
+	case OriginalAuto:
+	case OriginalImage, OriginalVideo:
+		if o.MaxWidth > 0 || o.MaxHeight > 0 {
+			return errors.New("original bytes can't be scaled: dimensions must be zero")
+		}
+	default:
+		return fmt.Errorf("invalid original kind %q", o.Original)
+	}
+	return nil
+}
+
+// params returns the base URL parameters to download the given media item.
+func (o DownloadOptions) params(mediaItem MediaItem) string {
+	if o.MaxWidth == 0 && o.MaxHeight == 0 {
+		switch {
+		case o.Original != OriginalAuto:
+			return string(o.Original)
+		case mediaItem.isVideo():
+			return string(OriginalVideo)
+		}
+		return string(OriginalImage)
+	}
+	var params []string
+	if o.MaxWidth > 0 {
+		params = append(params, "w"+strconv.FormatInt(o.MaxWidth, 10))
+	}
+	if o.MaxHeight > 0 {
+		params = append(params, "h"+strconv.FormatInt(o.MaxHeight, 10))
+	}
+	if o.Crop {
+		params = append(params, "c")
+	}
+	return strings.Join(params, "-")
+}
+
+func (m MediaItem) isVideo() bool {
+	return m.MediaMetadata.Video != nil || strings.HasPrefix(m.MimeType, "video/")
+}
+
+// Download returns the bytes of the media item, as requested by the options.
+// The caller must close the returned reader.
+//
+// The BaseURL of the media item expires 60 minutes after retrieving it, so the
+// media item is retrieved again before downloading it when its BaseURL is 55 minutes
+// old or older, or its RetrievedAt is not set.
+func (s *Service) Download(ctx context.Context, mediaItem MediaItem, options DownloadOptions) (io.ReadCloser, error) {
+	if err := options.validate(); err != nil {
+		return nil, fmt.Errorf("downloading media item %s: %w", mediaItem.ID, err)
+	}
+
+	if time.Since(mediaItem.RetrievedAt) >= baseURLRefreshAge {
+		refreshed, err := s.Get(ctx, mediaItem.ID)
+		if err != nil {
+			return nil, fmt.Errorf("downloading media item %s: refreshing base URL: %w", mediaItem.ID, err)
+		}
+		mediaItem = *refreshed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaItem.BaseURL+"="+options.params(mediaItem), nil)
+	if err != nil {
+		return nil, fmt.Errorf("downloading media item %s: %w", mediaItem.ID, err)
+	}
+	if options.Offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", options.Offset))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading media item %s: %w", mediaItem.ID, err)
+	}
+
+	switch {
+	case res.StatusCode == http.StatusPartialContent:
+		start, total, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err == nil && start != options.Offset {
+			err = fmt.Errorf("got range starting at %d, want %d", start, options.Offset)
+		}
+		if err != nil {
+			_ = res.Body.Close()
+			return nil, fmt.Errorf("downloading media item %s: %w", mediaItem.ID, err)
+		}
+		return newProgressBody(res.Body, mediaItem.ID, total, options), nil
+	case res.StatusCode == http.StatusOK:
+		// The server ignored the Range header, so the bytes already downloaded are skipped.
+		if _, err := io.CopyN(io.Discard, res.Body, options.Offset); err != nil {
+			_ = res.Body.Close()
+			return nil, fmt.Errorf("downloading media item %s: resuming at offset %d: %w", mediaItem.ID, options.Offset, err)
+		}
+		return newProgressBody(res.Body, mediaItem.ID, res.ContentLength, options), nil
+	default:
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("downloading media item %s: unexpected status %s", mediaItem.ID, res.Status)
+	}
+}
+
+// parseContentRange returns the first byte, and the complete length, of a Content-Range
+// header like "bytes 1000-1999/2000". The length is -1 when it's unknown.
+//
+// See: https://www.rfc-editor.org/rfc/rfc9110#name-content-range.
+func parseContentRange(header string) (start, total int64, err error) {
+	byteRange, length, ok := strings.Cut(strings.TrimPrefix(header, "bytes "), "/")
+	first, _, found := strings.Cut(byteRange, "-")
+	if !strings.HasPrefix(header, "bytes ") || !ok || !found {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	if length == "*" {
+		return start, -1, nil
+	}
+	if total, err = strconv.ParseInt(length, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	return start, total, nil
+}
+
+// progressBody reports the progress of a download as its body is read.
+type progressBody struct {
+	io.ReadCloser
+	fn       DownloadProgressFunc
+	progress DownloadProgress
+	start    time.Time
+	last     time.Time
+	done     bool
+}
+
+// newProgressBody returns body, reporting its progress to options.OnProgress, if set.
+func newProgressBody(body io.ReadCloser, mediaItemID string, total int64, options DownloadOptions) io.ReadCloser {
+	if options.OnProgress == nil {
+		return body
+	}
+	return &progressBody{
+		ReadCloser: body,
+		fn:         options.OnProgress,
+		progress: DownloadProgress{
+			MediaItemID: mediaItemID,
+			Received:    options.Offset,
+			Total:       total,
+			Offset:      options.Offset,
+		},
+		start: time.Now(),
+	}
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.progress.Received += int64(n)
+	if !b.done && (n > 0 || err == io.EOF) {
+		b.done = err == io.EOF
+		b.report(b.done)
+	}
+	return n, err
+}
+
+// report reports the progress. Reports are throttled, unless force is set.
+func (b *progressBody) report(force bool) {
+	now := time.Now()
+	if !force && !b.last.IsZero() && now.Sub(b.last) < downloadProgressInterval {
+		return
+	}
+	b.last = now
+	if elapsed := now.Sub(b.start).Seconds(); elapsed > 0 {
+		b.progress.Throughput = float64(b.progress.Received-b.progress.Offset) / elapsed
+	}
+	b.fn(b.progress)
+}
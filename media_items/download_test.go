@@ -0,0 +1,178 @@
+package media_items_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+)
+
+func TestMediaItemsService_Download(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	photo, err := m.Get(ctx, "fooId-0")
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	video, err := m.Get(ctx, "fooId-4")
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	expired := media_items.MediaItem{ID: "fooId-0", BaseURL: "http://expired.invalid/foo"}
+	expiring := media_items.MediaItem{ID: "fooId-0", BaseURL: "http://expired.invalid/foo", RetrievedAt: time.Now().Add(-56 * time.Minute)}
+
+	testCases := []struct {
+		name          string
+		mediaItem     media_items.MediaItem
+		options       media_items.DownloadOptions
+		want          []byte
+		isErrExpected bool
+	}{
+		{"Should download photo with metadata", *photo, media_items.DownloadOptions{}, mocks.FakeMediaBytes("fooId-0", "d"), false},
+		{"Should download video bytes", *video, media_items.DownloadOptions{}, mocks.FakeMediaBytes("fooId-4", "dv"), false},
+		{"Should download scaled photo", *photo, media_items.DownloadOptions{MaxWidth: 2048, MaxHeight: 1024}, mocks.FakeMediaBytes("fooId-0", "w2048-h1024"), false},
+		{"Should download cropped photo", *photo, media_items.DownloadOptions{MaxWidth: 256, MaxHeight: 256, Crop: true}, mocks.FakeMediaBytes("fooId-0", "w256-h256-c"), false},
+		{"Should resume download at offset", *photo, media_items.DownloadOptions{Offset: 1000}, mocks.FakeMediaBytes("fooId-0", "d")[1000:], false},
+		{"Should download video thumbnail with metadata", *video, media_items.DownloadOptions{Original: media_items.OriginalImage}, mocks.FakeMediaBytes("fooId-4", "d"), false},
+		{"Should download video bytes when asked", *video, media_items.DownloadOptions{Original: media_items.OriginalVideo}, mocks.FakeMediaBytes("fooId-4", "dv"), false},
+		{"Should refresh an expired base URL", expired, media_items.DownloadOptions{}, mocks.FakeMediaBytes("fooId-0", "d"), false},
+		{"Should refresh a base URL about to expire", expiring, media_items.DownloadOptions{}, mocks.FakeMediaBytes("fooId-0", "d"), false},
+		{"Should fail if refreshing the base URL fails", media_items.MediaItem{ID: "non-existent"}, media_items.DownloadOptions{}, nil, true},
+		{"Should fail with crop without dimensions", *photo, media_items.DownloadOptions{Crop: true, MaxWidth: 256}, nil, true},
+		{"Should fail with negative offset", *photo, media_items.DownloadOptions{Offset: -1}, nil, true},
+		{"Should fail with scaled original bytes", *photo, media_items.DownloadOptions{Original: media_items.OriginalImage, MaxWidth: 256}, nil, true},
+		{"Should fail with an invalid original kind", *photo, media_items.DownloadOptions{Original: "foo"}, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := m.Download(ctx, tc.mediaItem, tc.options)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if err != nil {
+				return
+			}
+			defer r.Close()
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if !bytes.Equal(tc.want, got) {
+				t.Errorf("want: %d bytes, got: %d bytes not matching", len(tc.want), len(got))
+			}
+		})
+	}
+}
+
+func TestMediaItemsService_Download_FreshBaseURL(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	// A recently retrieved media item is not refreshed, so its base URL is used as is.
+	item := media_items.MediaItem{ID: "fooId-0", BaseURL: srv.URL() + "/media/fooId-1", RetrievedAt: time.Now()}
+	r, err := m.Download(context.Background(), item, media_items.DownloadOptions{})
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if want := mocks.FakeMediaBytes("fooId-1", "d"); !bytes.Equal(want, got) {
+		t.Errorf("want: bytes of fooId-1, got: %d bytes not matching", len(got))
+	}
+}
+
+func TestMediaItemsService_Download_Progress(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	photo, err := m.Get(ctx, "fooId-0")
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	size := int64(len(mocks.FakeMediaBytes("fooId-0", "d")))
+
+	for _, offset := range []int64{0, 1000} {
+		var reports []media_items.DownloadProgress
+		r, err := m.Download(ctx, *photo, media_items.DownloadOptions{
+			Offset:     offset,
+			OnProgress: func(p media_items.DownloadProgress) { reports = append(reports, p) },
+		})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		_ = r.Close()
+
+		if len(reports) == 0 {
+			t.Fatalf("want: progress reports, got: none")
+		}
+		last := reports[len(reports)-1]
+		want := media_items.DownloadProgress{MediaItemID: "fooId-0", Received: size, Total: size, Offset: offset, Throughput: last.Throughput}
+		if want != last {
+			t.Errorf("want: %+v, got: %+v", want, last)
+		}
+	}
+}
+
+func TestMediaItemsService_Download_PartialContent(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	content := []byte("foo bar baz")
+	testCases := []struct {
+		name          string
+		contentRange  string
+		offset        int64
+		want          []byte
+		isErrExpected bool
+	}{
+		{"Should accept partial content from the beginning", "bytes 0-10/11", 0, content, false},
+		{"Should accept partial content at the offset", "bytes 4-10/11", 4, content[4:], false},
+		{"Should accept partial content of unknown length", "bytes 4-10/*", 4, content[4:], false},
+		{"Should fail with partial content at another offset", "bytes 0-10/11", 4, nil, true},
+		{"Should fail with partial content without range", "", 4, nil, true},
+		{"Should fail with an invalid range", "bytes foo-10/11", 4, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Responds with the bytes from the offset, whatever the range requested.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentRange != "" {
+					w.Header().Set("Content-Range", tc.contentRange)
+				}
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write(content[tc.offset:])
+			}))
+			defer server.Close()
+
+			item := media_items.MediaItem{ID: "fooId-0", BaseURL: server.URL + "/foo", RetrievedAt: time.Now()}
+			r, err := m.Download(context.Background(), item, media_items.DownloadOptions{Offset: tc.offset})
+			assertExpectedError(tc.isErrExpected, err, t)
+			if err != nil {
+				return
+			}
+			defer r.Close()
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if !bytes.Equal(tc.want, got) {
+				t.Errorf("want: %q, got: %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package media_items
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"google.golang.org/api/googleapi"
+)
+
+var (
+	// ErrMediaItemNotCreated is the error wrapped by every CreateError.
+	ErrMediaItemNotCreated = errors.New("media item not created")
+
+	// ErrMediaItemNotFound is the error returned when a media item is not found.
+	ErrMediaItemNotFound = errors.New("media item not found")
+
+	// ErrPermissionDenied is the error returned when the app is not allowed to access a media item.
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrNotAppCreated is the error returned when updating a media item that was not created by this app.
+	// The API only allows to update media items created by the app. It wraps the API error.
+	ErrNotAppCreated = errors.New("media item was not created by this app")
+)
+
+// google.rpc.Code values used by the API to report per item errors.
+//
+// See: https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto.
+const (
+	grpcUnknownCode          = 2
+	grpcNotFoundCode         = 5
+	grpcPermissionDeniedCode = 7
+)
+
+// CreateError is the error returned when a media item couldn't be created
+// from its upload token. It holds the status returned by the API.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/batchCreate#NewMediaItemResult.
+type CreateError struct {
+	// Index of the media item in the slice given to the create call.
+	Index int
+
+	// UploadToken of the media item that couldn't be created.
+	UploadToken string
+
+	// Code is the google.rpc.Code of the status, e.g. 3 for INVALID_ARGUMENT.
+	//
+	// See: https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto.
+	Code int64
+
+	// Message is the error message of the status.
+	Message string
+
+	// Err is the error of the call when the whole batch of media items failed, e.g. a *googleapi.Error.
+	// It's nil when the API returned a status for this media item.
+	Err error
+}
+
+func (e *CreateError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("creating media item with upload token %s: %s", e.UploadToken, e.Err)
+	}
+	return fmt.Sprintf("creating media item with upload token %s: code %d: %s", e.UploadToken, e.Code, e.Message)
+}
+
+func (e *CreateError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{ErrMediaItemNotCreated, e.Err}
+	}
+	return []error{ErrMediaItemNotCreated}
+}
+
+// BatchCreateError is the error returned when some media items of a create call
+// couldn't be created. The media items that were created are returned as well.
+//
+// Errors can be inspected with errors.Is(err, ErrMediaItemNotCreated) and
+// errors.As(err, &createErr), and UploadTokens can be used to retry only
+// the media items that failed.
+type BatchCreateError struct {
+	// Errors holds one CreateError for every media item that failed, in input order.
+	Errors []*CreateError
+
+	// Total is the number of media items in the create call.
+	Total int
+}
+
+func (e *BatchCreateError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("0 of %d media items were not created", e.Total)
+	}
+	return fmt.Sprintf("%d of %d media items were not created: %s", len(e.Errors), e.Total, e.Errors[0])
+}
+
+func (e *BatchCreateError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// UploadTokens returns the upload tokens of the media items that failed.
+func (e *BatchCreateError) UploadTokens() []string {
+	tokens := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		tokens[i] = err.UploadToken
+	}
+	return tokens
+}
+
+// GetError is the error returned when a media item couldn't be retrieved.
+// It holds the status returned by the API.
+//
+// Errors with a NOT_FOUND or PERMISSION_DENIED code match ErrMediaItemNotFound
+// and ErrPermissionDenied respectively, using errors.Is.
+type GetError struct {
+	// Index of the media item ID in the slice given to the get call.
+	Index int
+
+	// MediaItemID of the media item that couldn't be retrieved.
+	MediaItemID string
+
+	// Code is the google.rpc.Code of the status, e.g. 5 for NOT_FOUND.
+	Code int64
+
+	// Message is the error message of the status.
+	Message string
+
+	// Err is the error of the call when the whole batch of media items failed, e.g. a *googleapi.Error.
+	// It's nil when the API returned a status for this media item.
+	Err error
+}
+
+func (e *GetError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("getting media item %s: %s", e.MediaItemID, e.Err)
+	}
+	return fmt.Sprintf("getting media item %s: code %d: %s", e.MediaItemID, e.Code, e.Message)
+}
+
+func (e *GetError) Unwrap() []error {
+	var errs []error
+	switch e.Code {
+	case grpcNotFoundCode:
+		errs = append(errs, ErrMediaItemNotFound)
+	case grpcPermissionDeniedCode:
+		errs = append(errs, ErrPermissionDenied)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// BatchGetError is the error returned when some media items of a get call
+// couldn't be retrieved. The media items that were retrieved are returned as well.
+type BatchGetError struct {
+	// Errors holds one GetError for every media item that failed, in input order.
+	Errors []*GetError
+
+	// Total is the number of media items in the get call.
+	Total int
+}
+
+func (e *BatchGetError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("0 of %d media items were not retrieved", e.Total)
+	}
+	return fmt.Sprintf("%d of %d media items were not retrieved: %s", len(e.Errors), e.Total, e.Errors[0])
+}
+
+func (e *BatchGetError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// MediaItemIDs returns the IDs of the media items that failed.
+func (e *BatchGetError) MediaItemIDs() []string {
+	ids := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		ids[i] = err.MediaItemID
+	}
+	return ids
+}
+
+// translateGetError returns the package error for the known API errors of a get call.
+func translateGetError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return ErrMediaItemNotFound
+	}
+	return err
+}
+
+// translatePatchError wraps the known API errors of a patch call with the package error.
+// The API error is kept, because a 403 can also be caused by insufficient scopes or quotas.
+func translatePatchError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %w", ErrMediaItemNotFound, err)
+		case http.StatusForbidden:
+			// Insufficient scopes or quotas are forbidden too.
+			if isAppCreatedRestriction(apiErr) {
+				return fmt.Errorf("%w: %w", ErrNotAppCreated, err)
+			}
+		}
+	}
+	return err
+}
+
+// appCreatedRestriction matches the reasons of the errors returned when updating media items
+// that were not created by the app.
+var appCreatedRestriction = regexp.MustCompile(`(?i)app[- ]created|created by (the|this|your) (calling )?app`)
+
+// isAppCreatedRestriction returns true if the message, or the details, of the API error
+// show that the media item can't be updated because it was not created by the app.
+func isAppCreatedRestriction(apiErr *googleapi.Error) bool {
+	if appCreatedRestriction.MatchString(apiErr.Message) {
+		return true
+	}
+	for _, e := range apiErr.Errors {
+		if appCreatedRestriction.MatchString(e.Message) || appCreatedRestriction.MatchString(e.Reason) {
+			return true
+		}
+	}
+	for _, d := range apiErr.Details {
+		if appCreatedRestriction.MatchString(fmt.Sprint(d)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package media_items_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+)
+
+func TestMediaItemsService_CreateMany_PartialFailure(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	mediaItems := []media_items.SimpleMediaItem{
+		{UploadToken: "foo"},
+		{UploadToken: mocks.ShouldReturnEmptyMediaItem},
+		{UploadToken: "bar"},
+	}
+	got, err := m.CreateMany(context.Background(), mediaItems)
+
+	var batchErr *media_items.BatchCreateError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("want: %T, got: %v", batchErr, err)
+	}
+	if want := []string{mocks.ShouldReturnEmptyMediaItem}; !slices.Equal(want, batchErr.UploadTokens()) {
+		t.Errorf("want: %v, got: %v", want, batchErr.UploadTokens())
+	}
+	if !errors.Is(err, media_items.ErrMediaItemNotCreated) {
+		t.Errorf("want: %v, got: %v", media_items.ErrMediaItemNotCreated, err)
+	}
+
+	var createErr *media_items.CreateError
+	if !errors.As(err, &createErr) {
+		t.Fatalf("want: %T, got: %v", createErr, err)
+	}
+	if 1 != createErr.Index || 2 != createErr.Code || createErr.Message == "" {
+		t.Errorf("want: index 1, code 2 and a message, got: %+v", createErr)
+	}
+
+	if len(mediaItems) != len(got) {
+		t.Fatalf("want: %d, got: %d", len(mediaItems), len(got))
+	}
+	if got[0] == nil || got[1] != nil || got[2] == nil {
+		t.Errorf("want: only the second media item to be nil, got: %v", got)
+	}
+}
+
+func TestMediaItemsService_Create_Failure(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	_, err := m.Create(context.Background(), media_items.SimpleMediaItem{UploadToken: mocks.ShouldReturnEmptyMediaItem})
+
+	var createErr *media_items.CreateError
+	if !errors.As(err, &createErr) {
+		t.Fatalf("want: %T, got: %v", createErr, err)
+	}
+	if mocks.ShouldReturnEmptyMediaItem != createErr.UploadToken {
+		t.Errorf("want: %s, got: %s", mocks.ShouldReturnEmptyMediaItem, createErr.UploadToken)
+	}
+}
+
+func TestBatchErrors_Empty(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"BatchCreateError without errors", &media_items.BatchCreateError{Total: 3}, "0 of 3 media items were not created"},
+		{"BatchGetError without errors", &media_items.BatchGetError{Total: 3}, "0 of 3 media items were not retrieved"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.err.Error(); tc.want != got {
+				t.Errorf("want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package media_items
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/library"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/pagination"
+)
+
+// All returns an iterator over all the media items in the user's library, using the
+// 'mediaItems.list' method. Pages are retrieved as the iteration goes on, so media
+// items are not kept in memory.
+//
+// The iteration stops after yielding the first error, e.g. when ctx is cancelled.
+func (s *Service) All(ctx context.Context) iter.Seq2[MediaItem, error] {
+	return pagination.Seq(ctx, "", func(ctx context.Context, pageToken string) ([]MediaItem, string, error) {
+		res, err := s.photos.List().PageSize(maxMediaItemsPerPage).PageToken(pageToken).Context(ctx).Do()
+		if err != nil {
+			return nil, "", fmt.Errorf("listing media items: %w", err)
+		}
+		return toMediaItems(res.MediaItems), res.NextPageToken, nil
+	})
+}
+
+// ByAlbum returns an iterator over the media items in the specified album.
+// See All for details.
+func (s *Service) ByAlbum(ctx context.Context, albumId string) iter.Seq2[MediaItem, error] {
+	return s.searchSeq(ctx, &library.SearchMediaItemsRequest{
+		AlbumId:  albumId,
+		PageSize: maxMediaItemsPerPage,
+	})
+}
+
+// SearchSeq returns an iterator over the media items matching the given options,
+// starting at options.PageToken. See All for details.
+//
+// The options are validated before calling the API, yielding the validation error if any.
+func (s *Service) SearchSeq(ctx context.Context, options SearchOptions) iter.Seq2[MediaItem, error] {
+	if err := options.validate(); err != nil {
+		return func(yield func(MediaItem, error) bool) {
+			yield(MediaItem{}, fmt.Errorf("searching media items: %w", err))
+		}
+	}
+	return s.searchSeq(ctx, options.toRequest())
+}
+
+func (s *Service) searchSeq(ctx context.Context, req *library.SearchMediaItemsRequest) iter.Seq2[MediaItem, error] {
+	return pagination.Seq(ctx, req.PageToken, func(ctx context.Context, pageToken string) ([]MediaItem, string, error) {
+		req.PageToken = pageToken
+		res, err := s.photos.Search(req).Context(ctx).Do()
+		if err != nil {
+			return nil, "", fmt.Errorf("searching media items: %w", err)
+		}
+		return toMediaItems(res.MediaItems), res.NextPageToken, nil
+	})
+}
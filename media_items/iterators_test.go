@@ -0,0 +1,80 @@
+package media_items_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+)
+
+func TestMediaItemsService_Iterators(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	photos := media_items.SearchOptions{Filters: &media_items.SearchFilters{MediaType: media_items.MediaTypePhoto}, Limit: 50}
+
+	testCases := []struct {
+		name          string
+		seq           iter.Seq2[media_items.MediaItem, error]
+		want          int
+		isErrExpected bool
+	}{
+		{"All should iterate over all media items", m.All(ctx), mocks.AvailableMediaItems, false},
+		{"ByAlbum should iterate over the album", m.ByAlbum(ctx, mocks.ExistingAlbum.Id), mocks.AvailableMediaItems, false},
+		{"ByAlbum should fail if API fails", m.ByAlbum(ctx, mocks.ShouldFailAlbum.Id), 0, true},
+		{"SearchSeq should iterate over the results", m.SearchSeq(ctx, photos), 120, false},
+		{"SearchSeq should fail with invalid options", m.SearchSeq(ctx, media_items.SearchOptions{Limit: -1}), 0, true},
+		{"SearchSeq should fail if API fails", m.SearchSeq(ctx, media_items.SearchOptions{PageToken: mocks.PageTokenShouldFail}), 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := 0
+			var gotErr error
+			for item, err := range tc.seq {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				if item.ID == "" {
+					t.Errorf("want: media item ID, got: empty")
+				}
+				got++
+			}
+			assertExpectedError(tc.isErrExpected, gotErr, t)
+			if tc.want != got {
+				t.Errorf("want: %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMediaItemsService_All_Cancelled(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := 0
+	var gotErr error
+	for _, err := range m.All(ctx) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got++
+		if got == 120 {
+			cancel()
+		}
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("want: %v, got: %v", context.Canceled, gotErr)
+	}
+	if 120 != got {
+		t.Errorf("want: %d, got: %d", 120, got)
+	}
+}
@@ -2,9 +2,15 @@ package media_items
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/library"
 	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
 	"net/http"
+	"slices"
+	"time"
+	"unicode/utf8"
 )
 
 // A MediaItem represents a media item (e.g. photo, video etc.) in
@@ -41,21 +47,14 @@ type MediaItem struct {
 	// ProductURL: [Output only] Google Photos URL for the media item. This link
 	// will only be available to the user if they're signed in.
 	ProductURL string
-}
-
-// A MediaMetadata represents the metadata for a media item.
-//
-// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems.
-type MediaMetadata struct {
-	// CreationTime: [Output only] Time when the media item was first
-	// created (not when it was uploaded to Google Photos).
-	CreationTime string
 
-	// Height: [Output only] Original height (in pixels) of the media item.
-	Height int64
+	// ContributorInfo: [Output only] Information about the user who added this media item.
+	// It's only set for media items in shared albums created by this app.
+	ContributorInfo *ContributorInfo
 
-	// Width: [Output only] Original width (in pixels) of the media item.
-	Width int64
+	// RetrievedAt: Time when the media item was retrieved from the API.
+	// The BaseURL expires 60 minutes after this time.
+	RetrievedAt time.Time
 }
 
 // A SimpleMediaItem represents a simple media item to be created in Google
@@ -67,7 +66,36 @@ type SimpleMediaItem struct {
 	// uploaded to Google.
 	UploadToken string
 
+	// Filename: File name with extension of the media item. This is shown to
+	// the user in Google Photos. It must not include the directory.
 	Filename string
+
+	// Description: Description of the media item. This is shown to the user in
+	// the item's info section in the Google Photos app.
+	// Must be shorter than 1000 characters.
+	Description string
+}
+
+// maxDescriptionLength is the maximum number of characters of a media item description.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/batchCreate#NewMediaItem.
+const maxDescriptionLength = 1000
+
+func (m SimpleMediaItem) validate() error {
+	if l := utf8.RuneCountInString(m.Description); l >= maxDescriptionLength {
+		return fmt.Errorf("description is too long: %d characters, must be shorter than %d", l, maxDescriptionLength)
+	}
+	return nil
+}
+
+func (m SimpleMediaItem) toNewMediaItem() *library.NewMediaItem {
+	return &library.NewMediaItem{
+		Description: m.Description,
+		SimpleMediaItem: &library.SimpleMediaItem{
+			FileName:    m.Filename,
+			UploadToken: m.UploadToken,
+		},
+	}
 }
 
 // Config holds the configuration parameters for the service.
@@ -86,13 +114,36 @@ type Config struct {
 // Service implements a media items Google Photos client.
 type Service struct {
 	photos PhotosLibraryClient
+
+	// client is used to download the media items bytes.
+	client *http.Client
 }
 
 // PhotosLibraryClient represents a Google Photos client using `gphotosuploader/googlemirror/api/photoslibrary`.
 type PhotosLibraryClient interface {
-	BatchCreate(batchCreateMediaItemsRequest *photoslibrary.BatchCreateMediaItemsRequest) *photoslibrary.MediaItemsBatchCreateCall
+	BatchCreate(batchCreateMediaItemsRequest *library.BatchCreateMediaItemsRequest) *library.MediaItemsBatchCreateCall
+	BatchGet(mediaItemIds []string) *library.MediaItemsBatchGetCall
 	Get(mediaItemId string) *photoslibrary.MediaItemsGetCall
-	Search(searchMediaItemsRequest *photoslibrary.SearchMediaItemsRequest) *photoslibrary.MediaItemsSearchCall
+	List() *library.MediaItemsListCall
+	Patch(mediaItemId string, mediaItem *library.MediaItemPatch) *library.MediaItemsPatchCall
+	Search(searchMediaItemsRequest *library.SearchMediaItemsRequest) *library.MediaItemsSearchCall
+}
+
+// photosLibraryClient joins the media items calls from `gphotosuploader/googlemirror/api/photoslibrary`
+// with the ones missing there.
+type photosLibraryClient struct {
+	*photoslibrary.MediaItemsService
+	*library.MediaItems
+}
+
+// BatchCreate uses the batch create call supporting file names.
+func (c photosLibraryClient) BatchCreate(req *library.BatchCreateMediaItemsRequest) *library.MediaItemsBatchCreateCall {
+	return c.MediaItems.BatchCreate(req)
+}
+
+// Search uses the search call supporting all the filters and ordering.
+func (c photosLibraryClient) Search(req *library.SearchMediaItemsRequest) *library.MediaItemsSearchCall {
+	return c.MediaItems.Search(req)
 }
 
 // Create creates one media items in a user's Google Photos library.
@@ -110,61 +161,316 @@ func (s *Service) CreateMany(ctx context.Context, mediaItems []SimpleMediaItem)
 // CreateToAlbum creates one media items in a user's Google Photos library.
 // If an album id is specified, the media item is also added to the album.
 // By default, the media item will be added to the end of the library or album.
+//
+// A *CreateError is returned if the media item couldn't be created.
 func (s *Service) CreateToAlbum(ctx context.Context, albumId string, mediaItem SimpleMediaItem) (*MediaItem, error) {
-	result, err := s.CreateManyToAlbum(ctx, albumId, []SimpleMediaItem{mediaItem})
-	if err != nil {
-		return nil, err
-	}
-	return result[0], nil
+	return s.CreateWithOptions(ctx, mediaItem, CreateOptions{AlbumID: albumId})
 }
 
+// MaxMediaItemsPerBatch is the maximum number of media items that can be created, or retrieved, in one call.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/batchCreate.
+const MaxMediaItemsPerBatch = 50
+
 // CreateManyToAlbum creates one or more media item(s) in the repository.
 // If an album id is specified, the media item(s) is also added to the album.
 // By default, the media item(s) will be added to the end of the library or album.
+//
+// See CreateManyWithOptions for details about batches and errors.
 func (s *Service) CreateManyToAlbum(ctx context.Context, albumId string, mediaItems []SimpleMediaItem) ([]*MediaItem, error) {
-	newMediaItems := make([]*photoslibrary.NewMediaItem, len(mediaItems))
-	for i, mediaItem := range mediaItems {
-		newMediaItems[i] = &photoslibrary.NewMediaItem{
-			SimpleMediaItem: &photoslibrary.SimpleMediaItem{UploadToken: mediaItem.UploadToken},
+	return s.CreateManyWithOptions(ctx, mediaItems, CreateOptions{AlbumID: albumId})
+}
+
+// CreateOptions set the options for the CreateWithOptions and CreateManyWithOptions calls.
+type CreateOptions struct {
+	// AlbumID is the album where the media items are added. If empty, media items
+	// are only added to the library.
+	AlbumID string
+
+	// Position in the album where the media items are added. It requires AlbumID.
+	// The zero value adds the media items to the end of the album.
+	Position albums.AlbumPosition
+}
+
+// Validate returns an error if the options are not valid.
+func (o CreateOptions) Validate() error {
+	if err := o.Position.Validate(); err != nil {
+		return err
+	}
+	if o.Position != (albums.AlbumPosition{}) && o.AlbumID == "" {
+		return errors.New("album position requires an album ID")
+	}
+	return nil
+}
+
+// CreateWithOptions creates one media item in a user's Google Photos library,
+// optionally adding it to an album at a given position.
+//
+// A *CreateError is returned if the media item couldn't be created. Errors of the
+// request are returned as they are.
+func (s *Service) CreateWithOptions(ctx context.Context, mediaItem SimpleMediaItem, options CreateOptions) (*MediaItem, error) {
+	result, err := s.CreateManyWithOptions(ctx, []SimpleMediaItem{mediaItem}, options)
+	if batchErr := (*BatchCreateError)(nil); errors.As(err, &batchErr) {
+		return nil, batchErr.Errors[0]
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result[0], nil
+}
+
+// CreateManyWithOptions creates one or more media items in a user's Google Photos library,
+// optionally adding them to an album at a given position.
+//
+// Media items are created in batches of 50 items per call, keeping the input order,
+// also in the album: every batch is placed after the last media item created by the
+// previous one. When some media items couldn't be created, the result has nil entries
+// for them, and a *BatchCreateError is returned with the reason of every failure.
+//
+// A failed request, e.g. because of the network, doesn't stop the rest of the batches:
+// the media items of its batch are reported in the *BatchCreateError, with the error of
+// the request in CreateError.Err. Only when media items are added at a position in the
+// album, other than the end, a failed request stops the creation of the remaining batches,
+// because they are placed after the media items of the previous one. Its error is returned
+// as it is, along with the media items created so far. When ctx is done, the creation of
+// the remaining batches always stops, and its error is returned.
+func (s *Service) CreateManyWithOptions(ctx context.Context, mediaItems []SimpleMediaItem, options CreateOptions) ([]*MediaItem, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("creating media items: %w", err)
+	}
+	for _, mediaItem := range mediaItems {
+		if err := mediaItem.validate(); err != nil {
+			return nil, fmt.Errorf("creating media items: %w", err)
 		}
 	}
-	req := &photoslibrary.BatchCreateMediaItemsRequest{
+
+	mediaItemsResult := make([]*MediaItem, len(mediaItems))
+	var errs []*CreateError
+	position := options.Position
+	offset := 0
+	for chunk := range slices.Chunk(mediaItems, MaxMediaItemsPerBatch) {
+		if err := ctx.Err(); err != nil {
+			return mediaItemsResult, fmt.Errorf("creating media items: %w", err)
+		}
+		result := mediaItemsResult[offset : offset+len(chunk)]
+		batchErrs, err := s.createBatch(ctx, options.AlbumID, position, chunk, offset, result)
+		if err != nil && chainsPosition(position) {
+			return mediaItemsResult, fmt.Errorf("creating media items: %w", err)
+		}
+		if err != nil {
+			batchErrs = make([]*CreateError, len(chunk))
+			for i, mediaItem := range chunk {
+				batchErrs[i] = &CreateError{
+					Index:       offset + i,
+					UploadToken: mediaItem.UploadToken,
+					Code:        grpcUnknownCode,
+					Message:     "creating media items",
+					Err:         err,
+				}
+			}
+		}
+		errs = append(errs, batchErrs...)
+		position = nextPosition(position, result)
+		offset += len(chunk)
+	}
+	if len(errs) > 0 {
+		return mediaItemsResult, &BatchCreateError{Errors: errs, Total: len(mediaItems)}
+	}
+	return mediaItemsResult, nil
+}
+
+// chainsPosition returns true if every batch created at position is placed after the previous one.
+// Positions at the end of the album don't depend on the previous batches.
+func chainsPosition(position albums.AlbumPosition) bool {
+	return position != (albums.AlbumPosition{}) && position.Position != albums.PositionLastInAlbum
+}
+
+// nextPosition returns the position of the batch following the one that created mediaItems.
+// Positions at the end of the album don't change, the rest go after the last created media item.
+func nextPosition(position albums.AlbumPosition, mediaItems []*MediaItem) albums.AlbumPosition {
+	if !chainsPosition(position) {
+		return position
+	}
+	for i := len(mediaItems) - 1; i >= 0; i-- {
+		if mediaItems[i] != nil {
+			return albums.AfterMediaItem(mediaItems[i].ID)
+		}
+	}
+	return position
+}
+
+// createBatch creates up to MaxMediaItemsPerBatch media items, writing the created ones to result.
+// offset is the index of the first media item in the input, and it's used to report errors.
+// It returns the reason of every media item that couldn't be created, or the error of the request.
+func (s *Service) createBatch(ctx context.Context, albumId string, position albums.AlbumPosition, mediaItems []SimpleMediaItem, offset int, result []*MediaItem) ([]*CreateError, error) {
+	newMediaItems := make([]*library.NewMediaItem, len(mediaItems))
+	for i, mediaItem := range mediaItems {
+		newMediaItems[i] = mediaItem.toNewMediaItem()
+	}
+	req := &library.BatchCreateMediaItemsRequest{
 		AlbumId:       albumId,
 		NewMediaItems: newMediaItems,
 	}
-	result, err := s.photos.BatchCreate(req).Context(ctx).Do()
+	if position != (albums.AlbumPosition{}) {
+		req.AlbumPosition = position.ToAlbumPosition()
+	}
+	res, err := s.photos.BatchCreate(req).Context(ctx).Do()
 	if err != nil {
-		return nil, fmt.Errorf("creating media items: %w", err)
+		return nil, err
 	}
-	mediaItemsResult := make([]*MediaItem, len(result.NewMediaItemResults))
-	for i, res := range result.NewMediaItemResults {
+
+	var errs []*CreateError
+	for i, mediaItem := range mediaItems {
+		// Media items without a result are reported, instead of leaving them as silent nil entries.
+		if i >= len(res.NewMediaItemResults) || res.NewMediaItemResults[i] == nil {
+			errs = append(errs, toCreateError(offset+i, mediaItem, &photoslibrary.NewMediaItemResult{}))
+			continue
+		}
+		r := res.NewMediaItemResults[i]
 		// #54: MediaItem is populated if no errors occurred and the media item was
-		// created successfully.
-		// If an error occurs, res.Status should have more data about the error.
-		// In any case, we skip failed MediaItems.
-		//
-		// TODO: Log a message when res.MediaItem is nil. The res.Status should have the reason.
+		// created successfully. If an error occurs, r.Status has the reason.
 		//
 		// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/batchCreate#NewMediaItemResult.
-		if res.MediaItem != nil {
-			mi := toMediaItem(res.MediaItem)
-			mediaItemsResult[i] = &mi
+		if r.MediaItem != nil {
+			mi := toMediaItem(r.MediaItem)
+			result[i] = &mi
+			continue
 		}
+		errs = append(errs, toCreateError(offset+i, mediaItem, r))
+	}
+	return errs, nil
+}
 
+func toCreateError(index int, mediaItem SimpleMediaItem, res *photoslibrary.NewMediaItemResult) *CreateError {
+	e := &CreateError{
+		Index:       index,
+		UploadToken: mediaItem.UploadToken,
+		Code:        grpcUnknownCode,
+		Message:     "no media item was returned",
 	}
-	return mediaItemsResult, nil
+	if res.UploadToken != "" {
+		e.UploadToken = res.UploadToken
+	}
+	if res.Status != nil && res.Status.Code != 0 {
+		e.Code = res.Status.Code
+		e.Message = res.Status.Message
+	}
+	return e
 }
 
 // Get returns the media item specified based on a given media item id.
+// Returns [ErrMediaItemNotFound] if the media item does not exist.
 func (s *Service) Get(ctx context.Context, mediaItemId string) (*MediaItem, error) {
 	result, err := s.photos.Get(mediaItemId).Context(ctx).Do()
 	if err != nil {
-		return nil, fmt.Errorf("getting media item %s: %w", mediaItemId, err)
+		return nil, fmt.Errorf("getting media item %s: %w", mediaItemId, translateGetError(err))
 	}
 	m := toMediaItem(result)
 	return &m, nil
 }
 
+// PatchOptions set the media item fields to be updated by the Patch call.
+// Nil fields are left unchanged.
+type PatchOptions struct {
+	// Description: New description of the media item. An empty description clears it.
+	// Must be shorter than 1000 characters.
+	Description *string
+}
+
+// Patch updates the media item specified by the given media item id, and returns the updated media item.
+// Only media items created by this app can be updated.
+//
+// Returns [ErrNotAppCreated] if the media item was not created by this app, and
+// [ErrMediaItemNotFound] if the media item does not exist.
+func (s *Service) Patch(ctx context.Context, mediaItemId string, options PatchOptions) (*MediaItem, error) {
+	if options.Description == nil {
+		return nil, errors.New("updating media item: no fields to update")
+	}
+	if l := utf8.RuneCountInString(*options.Description); l >= maxDescriptionLength {
+		return nil, fmt.Errorf("updating media item: description is too long: %d characters, must be shorter than %d", l, maxDescriptionLength)
+	}
+
+	req := &library.MediaItemPatch{
+		Description: *options.Description,
+	}
+	res, err := s.photos.Patch(mediaItemId, req).UpdateMask("description").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("updating media item %s: %w", mediaItemId, translatePatchError(err))
+	}
+	m := toMediaItem(res)
+	return &m, nil
+}
+
+// UpdateDescription sets the description of the media item specified by the given media item id.
+// See Patch for details.
+func (s *Service) UpdateDescription(ctx context.Context, mediaItemId string, description string) (*MediaItem, error) {
+	return s.Patch(ctx, mediaItemId, PatchOptions{Description: &description})
+}
+
+// BatchGet returns the media items specified by the given media item IDs, in the same order.
+// Media items are retrieved in batches of 50 items per call.
+//
+// When some media items couldn't be retrieved, the result has nil entries for them,
+// and a *BatchGetError is returned with the reason of every failure, e.g. [ErrMediaItemNotFound].
+// A failed batch doesn't stop the rest of them.
+func (s *Service) BatchGet(ctx context.Context, mediaItemIds []string) ([]*MediaItem, error) {
+	mediaItemsResult := make([]*MediaItem, len(mediaItemIds))
+	var errs []*GetError
+	offset := 0
+	for chunk := range slices.Chunk(mediaItemIds, MaxMediaItemsPerBatch) {
+		errs = append(errs, s.getBatch(ctx, chunk, offset, mediaItemsResult[offset:offset+len(chunk)])...)
+		offset += len(chunk)
+	}
+	if len(errs) > 0 {
+		return mediaItemsResult, &BatchGetError{Errors: errs, Total: len(mediaItemIds)}
+	}
+	return mediaItemsResult, nil
+}
+
+// getBatch retrieves up to MaxMediaItemsPerBatch media items, writing the retrieved ones to result.
+// offset is the index of the first media item ID in the input, and it's used to report errors.
+func (s *Service) getBatch(ctx context.Context, mediaItemIds []string, offset int, result []*MediaItem) []*GetError {
+	res, err := s.photos.BatchGet(mediaItemIds).Context(ctx).Do()
+	if err != nil {
+		errs := make([]*GetError, len(mediaItemIds))
+		for i, id := range mediaItemIds {
+			errs[i] = &GetError{
+				Index:       offset + i,
+				MediaItemID: id,
+				Code:        grpcUnknownCode,
+				Message:     "getting media items",
+				Err:         err,
+			}
+		}
+		return errs
+	}
+
+	var errs []*GetError
+	for i, id := range mediaItemIds {
+		var r *library.MediaItemResult
+		if i < len(res.MediaItemResults) {
+			r = res.MediaItemResults[i]
+		}
+		if r != nil && r.MediaItem != nil {
+			mi := toMediaItem(r.MediaItem)
+			result[i] = &mi
+			continue
+		}
+		e := &GetError{
+			Index:       offset + i,
+			MediaItemID: id,
+			Code:        grpcUnknownCode,
+			Message:     "no media item was returned",
+		}
+		if r != nil && r.Status != nil && r.Status.Code != 0 {
+			e.Code = r.Status.Code
+			e.Message = r.Status.Message
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
 // maxMediaItemsPerPage is the maximum number of media items per page.
 // Fewer media items might be returned than the specified number.
 //
@@ -194,7 +500,7 @@ func (s *Service) PaginatedList(ctx context.Context, options *PaginatedListOptio
 		limit = maxMediaItemsPerPage
 	}
 
-	req := &photoslibrary.SearchMediaItemsRequest{
+	req := &library.SearchMediaItemsRequest{
 		AlbumId:   albumID,
 		PageSize:  limit,
 		PageToken: pageToken,
@@ -210,7 +516,7 @@ func (s *Service) PaginatedList(ctx context.Context, options *PaginatedListOptio
 
 // ListByAlbum list all media items in the specified album.
 func (s *Service) ListByAlbum(ctx context.Context, albumId string) ([]*MediaItem, error) {
-	req := &photoslibrary.SearchMediaItemsRequest{
+	req := &library.SearchMediaItemsRequest{
 		AlbumId:  albumId,
 		PageSize: maxMediaItemsPerPage,
 	}
@@ -249,8 +555,16 @@ func New(config Config) (*Service, error) {
 		s.UserAgent = config.UserAgent
 	}
 
+	l, err := library.New(config.Client)
+	if err != nil {
+		return nil, fmt.Errorf("creating service: %w", err)
+	}
+	l.BasePath = s.BasePath
+	l.UserAgent = s.UserAgent
+
 	service := &Service{
-		photos: s.MediaItems,
+		photos: photosLibraryClient{s.MediaItems, l.MediaItems},
+		client: config.Client,
 	}
 
 	return service, nil
@@ -258,17 +572,15 @@ func New(config Config) (*Service, error) {
 
 func toMediaItem(item *photoslibrary.MediaItem) MediaItem {
 	return MediaItem{
-		ID:          item.Id,
-		ProductURL:  item.ProductUrl,
-		BaseURL:     item.BaseUrl,
-		Description: item.Description,
-		MimeType:    item.MimeType,
-		Filename:    item.Filename,
-		MediaMetadata: MediaMetadata{
-			CreationTime: item.MediaMetadata.CreationTime,
-			Width:        item.MediaMetadata.Width,
-			Height:       item.MediaMetadata.Height,
-		},
+		ID:              item.Id,
+		ProductURL:      item.ProductUrl,
+		BaseURL:         item.BaseUrl,
+		Description:     item.Description,
+		MimeType:        item.MimeType,
+		Filename:        item.Filename,
+		MediaMetadata:   toMediaMetadata(item.MediaMetadata),
+		ContributorInfo: toContributorInfo(item.ContributorInfo),
+		RetrievedAt:     time.Now(),
 	}
 }
 
@@ -2,9 +2,15 @@ package media_items_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"google.golang.org/api/googleapi"
 	"net/http"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -73,6 +79,37 @@ func TestMediaItemsService_Create(t *testing.T) {
 	}
 }
 
+func TestMediaItemsService_Create_FilenameAndDescription(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mediaItem     media_items.SimpleMediaItem
+		isErrExpected bool
+	}{
+		{"Should keep filename and description", media_items.SimpleMediaItem{UploadToken: "foo", Filename: "foo.jpg", Description: "Foo description"}, false},
+		{"Should accept description of 999 characters", media_items.SimpleMediaItem{UploadToken: "foo", Description: strings.Repeat("ñ", 999)}, false},
+		{"Should fail with description of 1000 characters", media_items.SimpleMediaItem{UploadToken: "foo", Description: strings.Repeat("ñ", 1000)}, true},
+	}
+
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := m.Create(context.Background(), tc.mediaItem)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if err != nil {
+				return
+			}
+			if tc.mediaItem.Filename != "" && tc.mediaItem.Filename != got.Filename {
+				t.Errorf("want: %s, got: %s", tc.mediaItem.Filename, got.Filename)
+			}
+			if tc.mediaItem.Description != got.Description {
+				t.Errorf("want: %s, got: %s", tc.mediaItem.Description, got.Description)
+			}
+		})
+	}
+}
+
 func TestMediaItemsService_CreateMany(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -111,6 +148,151 @@ func TestMediaItemsService_CreateMany(t *testing.T) {
 	}
 }
 
+func TestMediaItemsService_CreateMany_InBatches(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	mediaItems := make([]media_items.SimpleMediaItem, 120)
+	for i := range mediaItems {
+		mediaItems[i].UploadToken = fmt.Sprintf("foo-%d", i)
+	}
+
+	t.Run("Should create all media items in order", func(t *testing.T) {
+		got, err := m.CreateMany(context.Background(), mediaItems)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if len(mediaItems) != len(got) {
+			t.Fatalf("want: %d, got: %d", len(mediaItems), len(got))
+		}
+		for i, item := range got {
+			if want := mediaItems[i].UploadToken + "Id"; want != item.ID {
+				t.Errorf("want: %s, got: %s", want, item.ID)
+			}
+		}
+	})
+
+	t.Run("Should continue when a request fails, reporting its media items", func(t *testing.T) {
+		failing := slices.Clone(mediaItems)
+		failing[60].UploadToken = mocks.ShouldMakeAPIFailMediaItem
+
+		got, err := m.CreateMany(context.Background(), failing)
+		var batchErr *media_items.BatchCreateError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("want: %T, got: %v", batchErr, err)
+		}
+		if 50 != len(batchErr.Errors) || 50 != batchErr.Errors[0].Index || 99 != batchErr.Errors[49].Index {
+			t.Errorf("want: media items 50 to 99 failed, got: %v", batchErr.Errors)
+		}
+		var apiErr *googleapi.Error
+		if !errors.As(batchErr.Errors[0], &apiErr) {
+			t.Errorf("want: %T, got: %v", apiErr, batchErr.Errors[0])
+		}
+		for i, item := range got {
+			created := i < 50 || i >= 100
+			if created != (item != nil) {
+				t.Errorf("media item %d: want created: %t, got: %v", i, created, item)
+			}
+		}
+	})
+
+	t.Run("Should stop when a request fails at a position, keeping the created media items", func(t *testing.T) {
+		failing := slices.Clone(mediaItems)
+		failing[60].UploadToken = mocks.ShouldMakeAPIFailMediaItem
+
+		got, err := m.CreateManyWithOptions(context.Background(), failing, media_items.CreateOptions{
+			AlbumID:  "fooAlbum",
+			Position: albums.AfterMediaItem("fooId-2"),
+		})
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("want: %T, got: %v", apiErr, err)
+		}
+		var batchErr *media_items.BatchCreateError
+		if errors.As(err, &batchErr) {
+			t.Errorf("want: request error, got: %v", batchErr)
+		}
+		for i, item := range got {
+			created := i < 50
+			if created != (item != nil) {
+				t.Errorf("media item %d: want created: %t, got: %v", i, created, item)
+			}
+		}
+	})
+
+	t.Run("Should report media items without result", func(t *testing.T) {
+		truncated := slices.Clone(mediaItems[:10])
+		truncated[7].UploadToken = mocks.ShouldReturnNoResult
+
+		got, err := m.CreateMany(context.Background(), truncated)
+		var batchErr *media_items.BatchCreateError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("want: %T, got: %v", batchErr, err)
+		}
+		if 3 != len(batchErr.Errors) || 7 != batchErr.Errors[0].Index {
+			t.Errorf("want: media items 7 to 9 failed, got: %v", batchErr.Errors)
+		}
+		for i, item := range got {
+			created := i < 7
+			if created != (item != nil) {
+				t.Errorf("media item %d: want created: %t, got: %v", i, created, item)
+			}
+		}
+	})
+
+	t.Run("Should stop when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := m.CreateMany(ctx, mediaItems); !errors.Is(err, context.Canceled) {
+			t.Errorf("want: %v, got: %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestMediaItemsService_CreateManyWithOptions(t *testing.T) {
+	newMediaItems := func(prefix string, n int) []media_items.SimpleMediaItem {
+		mediaItems := make([]media_items.SimpleMediaItem, n)
+		for i := range mediaItems {
+			mediaItems[i].UploadToken = fmt.Sprintf("%s-%d", prefix, i)
+		}
+		return mediaItems
+	}
+
+	testCases := []struct {
+		name          string
+		mediaItems    []media_items.SimpleMediaItem
+		options       media_items.CreateOptions
+		wantAt        int
+		isErrExpected bool
+	}{
+		{"Should add media items to the end of the album", newMediaItems("last", 3), media_items.CreateOptions{AlbumID: "fooAlbum"}, mocks.AvailableMediaItems, false},
+		{"Should add media items first in the album", newMediaItems("first", 60), media_items.CreateOptions{AlbumID: "fooAlbum", Position: albums.FirstInAlbum()}, 0, false},
+		{"Should add media items after a media item", newMediaItems("after", 60), media_items.CreateOptions{AlbumID: "fooAlbum", Position: albums.AfterMediaItem("fooId-2")}, 3, false},
+		{"Should fail with a position without album", newMediaItems("foo", 1), media_items.CreateOptions{Position: albums.FirstInAlbum()}, 0, true},
+		{"Should fail with an invalid position", newMediaItems("foo", 1), media_items.CreateOptions{AlbumID: "fooAlbum", Position: albums.AfterMediaItem("")}, 0, true},
+		{"Should fail after a media item not in the album", newMediaItems("foo", 1), media_items.CreateOptions{AlbumID: "fooAlbum", Position: albums.AfterMediaItem("non-existent")}, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, srv := newMediaItemsService(t)
+			defer srv.Close()
+
+			got, err := m.CreateManyWithOptions(context.Background(), tc.mediaItems, tc.options)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if err != nil {
+				return
+			}
+			contents := srv.AlbumMediaItems(tc.options.AlbumID)
+			for i, item := range got {
+				if want := contents[tc.wantAt+i]; want != item.ID {
+					t.Errorf("want: %s at %d, got: %s", want, tc.wantAt+i, item.ID)
+				}
+			}
+		})
+	}
+}
+
 func TestMediaItemsService_CreateToAlbum(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -218,6 +400,152 @@ func TestMediaItemsService_Get(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Should return ErrMediaItemNotFound if media item does not exist", func(t *testing.T) {
+		_, err := m.Get(context.Background(), "non-existent")
+		if !errors.Is(err, media_items.ErrMediaItemNotFound) {
+			t.Errorf("want: %v, got: %v", media_items.ErrMediaItemNotFound, err)
+		}
+	})
+}
+
+func TestMediaItemsService_Patch(t *testing.T) {
+	description := "Foo description"
+	empty := ""
+	tooLong := strings.Repeat("a", 1000)
+
+	testCases := []struct {
+		name          string
+		mediaItemId   string
+		options       media_items.PatchOptions
+		expectedError error
+		isErrExpected bool
+	}{
+		{"Should update the description", "fooId-0", media_items.PatchOptions{Description: &description}, nil, false},
+		{"Should clear the description", "fooId-0", media_items.PatchOptions{Description: &empty}, nil, false},
+		{"Should fail without fields to update", "fooId-0", media_items.PatchOptions{}, nil, true},
+		{"Should fail with a too long description", "fooId-0", media_items.PatchOptions{Description: &tooLong}, nil, true},
+		{"Should return ErrNotAppCreated if media item was not created by the app", "fooId-1", media_items.PatchOptions{Description: &description}, media_items.ErrNotAppCreated, true},
+		{"Should return ErrMediaItemNotFound if media item does not exist", "non-existent", media_items.PatchOptions{Description: &description}, media_items.ErrMediaItemNotFound, true},
+		{"Should return error if API fails", mocks.ShouldMakeAPIFailMediaItem, media_items.PatchOptions{Description: &description}, nil, true},
+	}
+
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := m.Patch(context.Background(), tc.mediaItemId, tc.options)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("not expected error, want: %v, got: %v", tc.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if *tc.options.Description != got.Description {
+				t.Errorf("want: %s, got: %s", *tc.options.Description, got.Description)
+			}
+		})
+	}
+
+	t.Run("Should keep the API error", func(t *testing.T) {
+		_, err := m.Patch(context.Background(), "fooId-1", media_items.PatchOptions{Description: &description})
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) || http.StatusForbidden != apiErr.Code {
+			t.Errorf("want: %T with code %d, got: %v", apiErr, http.StatusForbidden, err)
+		}
+	})
+
+	t.Run("Should return other forbidden errors unchanged", func(t *testing.T) {
+		_, err := m.Patch(context.Background(), mocks.PermissionDeniedMediaItem, media_items.PatchOptions{Description: &description})
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) || http.StatusForbidden != apiErr.Code {
+			t.Errorf("want: %T with code %d, got: %v", apiErr, http.StatusForbidden, err)
+		}
+		if errors.Is(err, media_items.ErrNotAppCreated) {
+			t.Errorf("want: not %v, got: %v", media_items.ErrNotAppCreated, err)
+		}
+	})
+}
+
+func TestMediaItemsService_UpdateDescription(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	got, err := m.UpdateDescription(context.Background(), "fooId-0", "Foo description")
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if "Foo description" != got.Description {
+		t.Errorf("want: %s, got: %s", "Foo description", got.Description)
+	}
+}
+
+func TestMediaItemsService_BatchGet(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	ids := make([]string, 120)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("fooId-%d", i)
+	}
+
+	t.Run("Should return all media items in order", func(t *testing.T) {
+		got, err := m.BatchGet(context.Background(), ids)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if len(ids) != len(got) {
+			t.Fatalf("want: %d, got: %d", len(ids), len(got))
+		}
+		for i, item := range got {
+			if ids[i] != item.ID {
+				t.Errorf("want: %s, got: %s", ids[i], item.ID)
+			}
+		}
+	})
+
+	t.Run("Should return per media item errors", func(t *testing.T) {
+		failing := slices.Clone(ids)
+		failing[10] = "non-existent"
+		failing[110] = mocks.PermissionDeniedMediaItem
+
+		got, err := m.BatchGet(context.Background(), failing)
+		var batchErr *media_items.BatchGetError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("want: %T, got: %v", batchErr, err)
+		}
+		if want := []string{"non-existent", mocks.PermissionDeniedMediaItem}; !slices.Equal(want, batchErr.MediaItemIDs()) {
+			t.Errorf("want: %v, got: %v", want, batchErr.MediaItemIDs())
+		}
+		if !errors.Is(batchErr.Errors[0], media_items.ErrMediaItemNotFound) {
+			t.Errorf("want: %v, got: %v", media_items.ErrMediaItemNotFound, batchErr.Errors[0])
+		}
+		if !errors.Is(batchErr.Errors[1], media_items.ErrPermissionDenied) {
+			t.Errorf("want: %v, got: %v", media_items.ErrPermissionDenied, batchErr.Errors[1])
+		}
+		if got[10] != nil || got[110] != nil || got[11] == nil {
+			t.Errorf("want: only failed media items to be nil")
+		}
+	})
+
+	t.Run("Should keep successful batches when a batch fails", func(t *testing.T) {
+		failing := slices.Clone(ids)
+		failing[0] = mocks.ShouldMakeAPIFailMediaItem
+
+		got, err := m.BatchGet(context.Background(), failing)
+		var batchErr *media_items.BatchGetError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("want: %T, got: %v", batchErr, err)
+		}
+		if 50 != len(batchErr.Errors) {
+			t.Errorf("want: %d, got: %d", 50, len(batchErr.Errors))
+		}
+		if got[49] != nil || got[50] == nil {
+			t.Errorf("want: only the first batch to be nil")
+		}
+	})
 }
 
 func TestMediaItemsService_ListByAlbum(t *testing.T) {
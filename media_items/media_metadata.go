@@ -0,0 +1,161 @@
+package media_items
+
+import (
+	"time"
+
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// A MediaMetadata represents the metadata for a media item.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems#MediaMetadata.
+type MediaMetadata struct {
+	// CreationTime: [Output only] Time when the media item was first
+	// created (not when it was uploaded to Google Photos).
+	// It's the zero time if the API didn't return a valid time.
+	CreationTime time.Time
+
+	// Height: [Output only] Original height (in pixels) of the media item.
+	Height int64
+
+	// Width: [Output only] Original width (in pixels) of the media item.
+	Width int64
+
+	// Photo: [Output only] Metadata for a photo media type. It's nil for videos.
+	Photo *PhotoMetadata
+
+	// Video: [Output only] Metadata for a video media type. It's nil for photos.
+	Video *VideoMetadata
+}
+
+// A PhotoMetadata represents the metadata that is specific to a photo,
+// such as, ISO, focal length and exposure time. Some of these fields may
+// be zero if the photo doesn't have them.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems#Photo.
+type PhotoMetadata struct {
+	// CameraMake: Brand of the camera with which the photo was taken.
+	CameraMake string
+
+	// CameraModel: Model of the camera with which the photo was taken.
+	CameraModel string
+
+	// FocalLength: Focal length of the camera lens with which the photo was taken.
+	FocalLength float64
+
+	// ApertureFNumber: Aperture f number of the camera lens with which the photo was taken.
+	ApertureFNumber float64
+
+	// IsoEquivalent: ISO of the camera with which the photo was taken.
+	IsoEquivalent int64
+
+	// ExposureTime: Exposure time of the camera aperture when the photo was taken.
+	ExposureTime time.Duration
+}
+
+// VideoProcessingStatus is the processing status of a video being uploaded to Google Photos.
+type VideoProcessingStatus string
+
+// Video processing statuses.
+const (
+	// VideoStatusUnspecified means the video processing status is unknown.
+	VideoStatusUnspecified VideoProcessingStatus = "UNSPECIFIED"
+
+	// VideoStatusProcessing means the video is being processed. The user sees an icon for this video
+	// in the Google Photos app; however, it isn't playable yet.
+	VideoStatusProcessing VideoProcessingStatus = "PROCESSING"
+
+	// VideoStatusReady means the video processing is complete and it is now ready for viewing.
+	VideoStatusReady VideoProcessingStatus = "READY"
+
+	// VideoStatusFailed means something has gone wrong and the video has failed to process.
+	VideoStatusFailed VideoProcessingStatus = "FAILED"
+)
+
+// A VideoMetadata represents the metadata that is specific to a video,
+// for example, fps and processing status. Some of these fields may be
+// zero if the video doesn't have them.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems#Video.
+type VideoMetadata struct {
+	// CameraMake: Brand of the camera with which the video was taken.
+	CameraMake string
+
+	// CameraModel: Model of the camera with which the video was taken.
+	CameraModel string
+
+	// Fps: Frame rate of the video.
+	Fps float64
+
+	// Status: Processing status of the video.
+	Status VideoProcessingStatus
+}
+
+// A ContributorInfo represents information about the user who added the media item.
+// It's only set for media items in shared albums created by this app.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems#ContributorInfo.
+type ContributorInfo struct {
+	// ProfilePictureBaseURL: URL to the profile picture of the contributor.
+	ProfilePictureBaseURL string
+
+	// DisplayName: Display name of the contributor.
+	DisplayName string
+}
+
+func toMediaMetadata(m *photoslibrary.MediaMetadata) MediaMetadata {
+	if m == nil {
+		return MediaMetadata{}
+	}
+	// A wrong creation time shouldn't make the whole media item unusable.
+	creationTime, _ := time.Parse(time.RFC3339Nano, m.CreationTime)
+	return MediaMetadata{
+		CreationTime: creationTime,
+		Height:       m.Height,
+		Width:        m.Width,
+		Photo:        toPhotoMetadata(m.Photo),
+		Video:        toVideoMetadata(m.Video),
+	}
+}
+
+func toPhotoMetadata(p *photoslibrary.Photo) *PhotoMetadata {
+	if p == nil {
+		return nil
+	}
+	// ExposureTime is a duration in seconds with up to nine fractional digits, ending with 's'.
+	exposureTime, _ := time.ParseDuration(p.ExposureTime)
+	return &PhotoMetadata{
+		CameraMake:      p.CameraMake,
+		CameraModel:     p.CameraModel,
+		FocalLength:     p.FocalLength,
+		ApertureFNumber: p.ApertureFNumber,
+		IsoEquivalent:   p.IsoEquivalent,
+		ExposureTime:    exposureTime,
+	}
+}
+
+func toVideoMetadata(v *photoslibrary.Video) *VideoMetadata {
+	if v == nil {
+		return nil
+	}
+	status := VideoProcessingStatus(v.Status)
+	if status == "" {
+		status = VideoStatusUnspecified
+	}
+	return &VideoMetadata{
+		CameraMake:  v.CameraMake,
+		CameraModel: v.CameraModel,
+		Fps:         v.Fps,
+		Status:      status,
+	}
+}
+
+func toContributorInfo(c *photoslibrary.ContributorInfo) *ContributorInfo {
+	if c == nil {
+		return nil
+	}
+	return &ContributorInfo{
+		ProfilePictureBaseURL: c.ProfilePictureBaseUrl,
+		DisplayName:           c.DisplayName,
+	}
+}
@@ -0,0 +1,66 @@
+package media_items_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+)
+
+func TestMediaItemsService_Get_MediaMetadata(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	t.Run("Should map photo metadata", func(t *testing.T) {
+		got, err := m.Get(ctx, "fooId-6")
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		wantTime := time.Date(2014, time.October, 8, 15, 1, 23, 45123456, time.UTC)
+		if !wantTime.Equal(got.MediaMetadata.CreationTime) {
+			t.Errorf("want: %s, got: %s", wantTime, got.MediaMetadata.CreationTime)
+		}
+		if got.MediaMetadata.Video != nil {
+			t.Errorf("want: no video metadata, got: %+v", got.MediaMetadata.Video)
+		}
+		want := media_items.PhotoMetadata{
+			CameraMake:      "fooCameraMake",
+			CameraModel:     "fooCameraModel",
+			FocalLength:     4.25,
+			ApertureFNumber: 1.8,
+			IsoEquivalent:   100,
+			ExposureTime:    8 * time.Millisecond,
+		}
+		if got.MediaMetadata.Photo == nil || want != *got.MediaMetadata.Photo {
+			t.Errorf("want: %+v, got: %+v", want, got.MediaMetadata.Photo)
+		}
+		if got.ContributorInfo == nil || "fooContributor-6" != got.ContributorInfo.DisplayName {
+			t.Errorf("want: contributor fooContributor-6, got: %+v", got.ContributorInfo)
+		}
+	})
+
+	t.Run("Should map video metadata", func(t *testing.T) {
+		got, err := m.Get(ctx, "fooId-4")
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if got.MediaMetadata.Photo != nil {
+			t.Errorf("want: no photo metadata, got: %+v", got.MediaMetadata.Photo)
+		}
+		want := media_items.VideoMetadata{
+			CameraMake:  "fooCameraMake",
+			CameraModel: "fooCameraModel",
+			Fps:         30,
+			Status:      media_items.VideoStatusReady,
+		}
+		if got.MediaMetadata.Video == nil || want != *got.MediaMetadata.Video {
+			t.Errorf("want: %+v, got: %+v", want, got.MediaMetadata.Video)
+		}
+		if got.ContributorInfo != nil {
+			t.Errorf("want: no contributor, got: %+v", got.ContributorInfo)
+		}
+	})
+}
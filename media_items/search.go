@@ -0,0 +1,345 @@
+package media_items
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/library"
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// ContentCategory is a category of the contents of a media item.
+//
+// See: https://developers.google.com/photos/library/guides/apply-filters#content-categories.
+type ContentCategory string
+
+// Content categories that can be used in [SearchFilters].
+const (
+	ContentCategoryNone         ContentCategory = "NONE"
+	ContentCategoryAnimals      ContentCategory = "ANIMALS"
+	ContentCategoryArts         ContentCategory = "ARTS"
+	ContentCategoryBirthdays    ContentCategory = "BIRTHDAYS"
+	ContentCategoryCityscapes   ContentCategory = "CITYSCAPES"
+	ContentCategoryCrafts       ContentCategory = "CRAFTS"
+	ContentCategoryDocuments    ContentCategory = "DOCUMENTS"
+	ContentCategoryFashion      ContentCategory = "FASHION"
+	ContentCategoryFlowers      ContentCategory = "FLOWERS"
+	ContentCategoryFood         ContentCategory = "FOOD"
+	ContentCategoryGardens      ContentCategory = "GARDENS"
+	ContentCategoryHolidays     ContentCategory = "HOLIDAYS"
+	ContentCategoryHouses       ContentCategory = "HOUSES"
+	ContentCategoryLandmarks    ContentCategory = "LANDMARKS"
+	ContentCategoryLandscapes   ContentCategory = "LANDSCAPES"
+	ContentCategoryNight        ContentCategory = "NIGHT"
+	ContentCategoryPeople       ContentCategory = "PEOPLE"
+	ContentCategoryPerformances ContentCategory = "PERFORMANCES"
+	ContentCategoryPets         ContentCategory = "PETS"
+	ContentCategoryReceipts     ContentCategory = "RECEIPTS"
+	ContentCategoryScreenshots  ContentCategory = "SCREENSHOTS"
+	ContentCategorySelfies      ContentCategory = "SELFIES"
+	ContentCategorySport        ContentCategory = "SPORT"
+	ContentCategoryTravel       ContentCategory = "TRAVEL"
+	ContentCategoryUtility      ContentCategory = "UTILITY"
+	ContentCategoryWeddings     ContentCategory = "WEDDINGS"
+	ContentCategoryWhiteboards  ContentCategory = "WHITEBOARDS"
+)
+
+// MediaType is the type of media of a media item.
+type MediaType string
+
+// Media types that can be used in [SearchFilters].
+const (
+	MediaTypeAll   MediaType = "ALL_MEDIA"
+	MediaTypePhoto MediaType = "PHOTO"
+	MediaTypeVideo MediaType = "VIDEO"
+)
+
+// Feature is a feature of a media item.
+type Feature string
+
+// Features that can be used in [SearchFilters].
+const (
+	FeatureNone      Feature = "NONE"
+	FeatureFavorites Feature = "FAVORITES"
+)
+
+// OrderBy is the sort order of the search results.
+type OrderBy string
+
+// Sort orders that can be used in [SearchOptions].
+const (
+	// OrderByCreationTime sorts the results by creation time, oldest first.
+	OrderByCreationTime OrderBy = "MediaMetadata.creation_time"
+
+	// OrderByCreationTimeDesc sorts the results by creation time, newest first.
+	OrderByCreationTimeDesc OrderBy = "MediaMetadata.creation_time desc"
+)
+
+// A Date represents a whole or partial calendar date.
+// Year, Month or Day can be 0 to match any year, month or day, but a Date
+// can't have only the Day or be all zeros.
+type Date struct {
+	// Year of the date. Must be from 1 to 9999, or 0 to match any year.
+	Year int
+
+	// Month of the year. Must be from 1 to 12, or 0 to match any month.
+	Month int
+
+	// Day of the month. Must be from 1 to 31, or 0 to match any day.
+	Day int
+}
+
+func (d Date) validate() error {
+	if d.Year < 0 || d.Year > 9999 {
+		return fmt.Errorf("invalid year %d", d.Year)
+	}
+	if d.Month < 0 || d.Month > 12 {
+		return fmt.Errorf("invalid month %d", d.Month)
+	}
+	if d.Day < 0 || d.Day > 31 {
+		return fmt.Errorf("invalid day %d", d.Day)
+	}
+	if d.Year == 0 && d.Month == 0 {
+		return errors.New("invalid date: year or month are required")
+	}
+	return nil
+}
+
+func (d Date) toDate() *photoslibrary.Date {
+	return &photoslibrary.Date{
+		Year:  int64(d.Year),
+		Month: int64(d.Month),
+		Day:   int64(d.Day),
+	}
+}
+
+// A DateRange represents a range of dates. Both dates must have the same format.
+type DateRange struct {
+	// Start date, included in the range.
+	Start Date
+
+	// End date, included in the range.
+	End Date
+}
+
+// Search limits imposed by the API.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/mediaItems/search#filters.
+const (
+	maxDatesPerFilter      = 5
+	maxCategoriesPerFilter = 10
+)
+
+// SearchFilters set the filters to be applied to a media items search.
+// If multiple filters are specified, they're treated as AND with each other.
+//
+// See: https://developers.google.com/photos/library/guides/apply-filters.
+type SearchFilters struct {
+	// Dates the media items must have been created on. Up to 5 dates.
+	Dates []Date
+
+	// DateRanges the media items must have been created in. Up to 5 ranges.
+	DateRanges []DateRange
+
+	// IncludedContentCategories are the categories that must be present in the media items. Up to 10 categories.
+	IncludedContentCategories []ContentCategory
+
+	// ExcludedContentCategories are the categories that must not be present in the media items. Up to 10 categories.
+	ExcludedContentCategories []ContentCategory
+
+	// MediaType of the media items. Empty means all media types.
+	MediaType MediaType
+
+	// IncludedFeatures the media items must have, e.g. [FeatureFavorites].
+	IncludedFeatures []Feature
+
+	// IncludeArchivedMedia includes the media items that the user has archived.
+	IncludeArchivedMedia bool
+
+	// ExcludeNonAppCreatedData excludes the media items that were not created by this app.
+	ExcludeNonAppCreatedData bool
+}
+
+func (f *SearchFilters) hasDateFilter() bool {
+	return f != nil && (len(f.Dates) > 0 || len(f.DateRanges) > 0)
+}
+
+func (f *SearchFilters) validate() error {
+	if len(f.Dates) > maxDatesPerFilter {
+		return fmt.Errorf("too many dates: %d, maximum is %d", len(f.Dates), maxDatesPerFilter)
+	}
+	if len(f.DateRanges) > maxDatesPerFilter {
+		return fmt.Errorf("too many date ranges: %d, maximum is %d", len(f.DateRanges), maxDatesPerFilter)
+	}
+	for _, d := range f.Dates {
+		if err := d.validate(); err != nil {
+			return err
+		}
+	}
+	for _, r := range f.DateRanges {
+		if err := r.Start.validate(); err != nil {
+			return fmt.Errorf("range start: %w", err)
+		}
+		if err := r.End.validate(); err != nil {
+			return fmt.Errorf("range end: %w", err)
+		}
+		if (r.Start.Year == 0) != (r.End.Year == 0) || (r.Start.Month == 0) != (r.End.Month == 0) || (r.Start.Day == 0) != (r.End.Day == 0) {
+			return errors.New("range start and end dates must have the same format")
+		}
+	}
+	if len(f.IncludedContentCategories) > maxCategoriesPerFilter {
+		return fmt.Errorf("too many included content categories: %d, maximum is %d", len(f.IncludedContentCategories), maxCategoriesPerFilter)
+	}
+	if len(f.ExcludedContentCategories) > maxCategoriesPerFilter {
+		return fmt.Errorf("too many excluded content categories: %d, maximum is %d", len(f.ExcludedContentCategories), maxCategoriesPerFilter)
+	}
+	for _, c := range f.IncludedContentCategories {
+		if slices.Contains(f.ExcludedContentCategories, c) {
+			return fmt.Errorf("content category %s can't be included and excluded", c)
+		}
+	}
+	switch f.MediaType {
+	case "", MediaTypeAll, MediaTypePhoto, MediaTypeVideo:
+	default:
+		return fmt.Errorf("unknown media type %q", f.MediaType)
+	}
+	return nil
+}
+
+func (f *SearchFilters) toFilters() *library.Filters {
+	filters := &library.Filters{
+		IncludeArchivedMedia:     f.IncludeArchivedMedia,
+		ExcludeNonAppCreatedData: f.ExcludeNonAppCreatedData,
+	}
+	if f.hasDateFilter() {
+		filters.DateFilter = &photoslibrary.DateFilter{}
+		for _, d := range f.Dates {
+			filters.DateFilter.Dates = append(filters.DateFilter.Dates, d.toDate())
+		}
+		for _, r := range f.DateRanges {
+			filters.DateFilter.Ranges = append(filters.DateFilter.Ranges, &photoslibrary.DateRange{
+				StartDate: r.Start.toDate(),
+				EndDate:   r.End.toDate(),
+			})
+		}
+	}
+	if len(f.IncludedContentCategories) > 0 || len(f.ExcludedContentCategories) > 0 {
+		filters.ContentFilter = &photoslibrary.ContentFilter{
+			IncludedContentCategories: toStrings(f.IncludedContentCategories),
+			ExcludedContentCategories: toStrings(f.ExcludedContentCategories),
+		}
+	}
+	if f.MediaType != "" {
+		filters.MediaTypeFilter = &photoslibrary.MediaTypeFilter{
+			MediaTypes: []string{string(f.MediaType)},
+		}
+	}
+	if len(f.IncludedFeatures) > 0 {
+		filters.FeatureFilter = &library.FeatureFilter{
+			IncludedFeatures: toStrings(f.IncludedFeatures),
+		}
+	}
+	return filters
+}
+
+func toStrings[T ~string](values []T) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = string(v)
+	}
+	return result
+}
+
+// SearchOptions set the options for the Search and SearchAll calls.
+type SearchOptions struct {
+	// AlbumID lists the media items in the album. It can't be used with Filters.
+	AlbumID string
+
+	// Filters to apply to the search. They can't be used with AlbumID.
+	Filters *SearchFilters
+
+	// OrderBy sets the sort order of the results. It requires a date filter.
+	OrderBy OrderBy
+
+	// Limit is the maximum number of media items per page, up to 100.
+	Limit int64
+
+	// PageToken is the token of the page to retrieve.
+	PageToken string
+}
+
+// validate checks the options locally, to avoid spending API quota on invalid requests.
+func (o SearchOptions) validate() error {
+	if o.AlbumID != "" && o.Filters != nil {
+		return errors.New("album ID and filters can't be used together")
+	}
+	if o.Filters != nil {
+		if err := o.Filters.validate(); err != nil {
+			return err
+		}
+	}
+	switch o.OrderBy {
+	case "":
+	case OrderByCreationTime, OrderByCreationTimeDesc:
+		if !o.Filters.hasDateFilter() {
+			return errors.New("order by requires a date filter")
+		}
+	default:
+		return fmt.Errorf("unknown order by %q", o.OrderBy)
+	}
+	if o.Limit < 0 || o.Limit > maxMediaItemsPerPage {
+		return fmt.Errorf("invalid limit %d, maximum is %d", o.Limit, maxMediaItemsPerPage)
+	}
+	return nil
+}
+
+func (o SearchOptions) toRequest() *library.SearchMediaItemsRequest {
+	req := &library.SearchMediaItemsRequest{
+		AlbumId:   o.AlbumID,
+		PageSize:  o.Limit,
+		PageToken: o.PageToken,
+		OrderBy:   string(o.OrderBy),
+	}
+	if req.PageSize == 0 {
+		req.PageSize = maxMediaItemsPerPage
+	}
+	if o.Filters != nil {
+		req.Filters = o.Filters.toFilters()
+	}
+	return req
+}
+
+// Search retrieves a page of the media items matching the given options.
+// The options are validated before calling the API.
+func (s *Service) Search(ctx context.Context, options SearchOptions) (mediaItems []MediaItem, nextPageToken string, err error) {
+	if err := options.validate(); err != nil {
+		return nil, "", fmt.Errorf("searching media items: %w", err)
+	}
+
+	response, err := s.photos.Search(options.toRequest()).Context(ctx).Do()
+	if err != nil {
+		return nil, "", fmt.Errorf("searching media items: %w", err)
+	}
+
+	return toMediaItems(response.MediaItems), response.NextPageToken, nil
+}
+
+// SearchAll retrieves all the media items matching the given options, starting at options.PageToken.
+// The options are validated before calling the API.
+func (s *Service) SearchAll(ctx context.Context, options SearchOptions) ([]MediaItem, error) {
+	if err := options.validate(); err != nil {
+		return nil, fmt.Errorf("searching media items: %w", err)
+	}
+
+	var result []MediaItem
+	err := s.photos.Search(options.toRequest()).Pages(ctx, func(response *photoslibrary.SearchMediaItemsResponse) error {
+		result = append(result, toMediaItems(response.MediaItems)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching media items: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package media_items_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+)
+
+func TestMediaItemsService_Search(t *testing.T) {
+	oneDay := media_items.Date{Year: 2014, Month: 10, Day: 2}
+	tenDays := media_items.DateRange{
+		Start: media_items.Date{Year: 2014, Month: 10, Day: 2},
+		End:   media_items.Date{Year: 2014, Month: 10, Day: 11},
+	}
+
+	testCases := []struct {
+		name          string
+		options       media_items.SearchOptions
+		want          int
+		wantFirstID   string
+		isErrExpected bool
+	}{
+		{"Should return all media items without filters", media_items.SearchOptions{}, mocks.AvailableMediaItems, "fooId-0", false},
+		{"Should filter by date", media_items.SearchOptions{Filters: &media_items.SearchFilters{Dates: []media_items.Date{oneDay}}}, 1, "fooId-0", false},
+		{"Should filter by date range", media_items.SearchOptions{Filters: &media_items.SearchFilters{DateRanges: []media_items.DateRange{tenDays}}}, 10, "fooId-0", false},
+		{"Should order by creation time desc", media_items.SearchOptions{Filters: &media_items.SearchFilters{DateRanges: []media_items.DateRange{tenDays}}, OrderBy: media_items.OrderByCreationTimeDesc}, 10, "fooId-9", false},
+		{"Should filter by media type", media_items.SearchOptions{Filters: &media_items.SearchFilters{MediaType: media_items.MediaTypeVideo}}, 30, "fooId-4", false},
+		{"Should filter by content category", media_items.SearchOptions{Filters: &media_items.SearchFilters{IncludedContentCategories: []media_items.ContentCategory{media_items.ContentCategoryPets}}}, 50, "fooId-1", false},
+		{"Should exclude content category", media_items.SearchOptions{Filters: &media_items.SearchFilters{ExcludedContentCategories: []media_items.ContentCategory{media_items.ContentCategoryLandscapes}}}, 100, "fooId-1", false},
+		{"Should filter by feature", media_items.SearchOptions{Filters: &media_items.SearchFilters{IncludedFeatures: []media_items.Feature{media_items.FeatureFavorites}}}, 15, "fooId-0", false},
+		{"Should include archived media", media_items.SearchOptions{Filters: &media_items.SearchFilters{IncludeArchivedMedia: true}}, mocks.AvailableMediaItems + mocks.ArchivedMediaItems, "fooId-0", false},
+		{"Should exclude non app created data", media_items.SearchOptions{Filters: &media_items.SearchFilters{ExcludeNonAppCreatedData: true}}, 75, "fooId-0", false},
+		{"Should combine filters", media_items.SearchOptions{Filters: &media_items.SearchFilters{DateRanges: []media_items.DateRange{tenDays}, MediaType: media_items.MediaTypePhoto}}, 8, "fooId-0", false},
+		{"Should list media items in album", media_items.SearchOptions{AlbumID: mocks.ExistingAlbum.Id}, mocks.AvailableMediaItems, "fooId-0", false},
+		{"Should fail if API fails", media_items.SearchOptions{AlbumID: mocks.ShouldFailAlbum.Id}, 0, "", true},
+		{"Should fail with album ID and filters", media_items.SearchOptions{AlbumID: mocks.ExistingAlbum.Id, Filters: &media_items.SearchFilters{}}, 0, "", true},
+		{"Should fail ordering without date filter", media_items.SearchOptions{OrderBy: media_items.OrderByCreationTime}, 0, "", true},
+		{"Should fail with unknown order", media_items.SearchOptions{Filters: &media_items.SearchFilters{Dates: []media_items.Date{oneDay}}, OrderBy: "foo"}, 0, "", true},
+		{"Should fail with invalid date", media_items.SearchOptions{Filters: &media_items.SearchFilters{Dates: []media_items.Date{{Month: 13}}}}, 0, "", true},
+		{"Should fail with only day in date", media_items.SearchOptions{Filters: &media_items.SearchFilters{Dates: []media_items.Date{{Day: 1}}}}, 0, "", true},
+		{"Should fail with too many dates", media_items.SearchOptions{Filters: &media_items.SearchFilters{Dates: []media_items.Date{oneDay, oneDay, oneDay, oneDay, oneDay, oneDay}}}, 0, "", true},
+		{"Should fail with range of different formats", media_items.SearchOptions{Filters: &media_items.SearchFilters{DateRanges: []media_items.DateRange{{Start: oneDay, End: media_items.Date{Year: 2015}}}}}, 0, "", true},
+		{"Should fail with included and excluded category", media_items.SearchOptions{Filters: &media_items.SearchFilters{IncludedContentCategories: []media_items.ContentCategory{media_items.ContentCategoryFood}, ExcludedContentCategories: []media_items.ContentCategory{media_items.ContentCategoryFood}}}, 0, "", true},
+		{"Should fail with unknown media type", media_items.SearchOptions{Filters: &media_items.SearchFilters{MediaType: "foo"}}, 0, "", true},
+		{"Should fail with invalid limit", media_items.SearchOptions{Limit: 101}, 0, "", true},
+	}
+
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := m.SearchAll(context.Background(), tc.options)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if err != nil {
+				return
+			}
+			if tc.want != len(got) {
+				t.Fatalf("want: %d, got: %d", tc.want, len(got))
+			}
+			if tc.wantFirstID != got[0].ID {
+				t.Errorf("want: %s, got: %s", tc.wantFirstID, got[0].ID)
+			}
+		})
+	}
+}
+
+func TestMediaItemsService_Search_Paginated(t *testing.T) {
+	m, srv := newMediaItemsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	options := media_items.SearchOptions{
+		Filters: &media_items.SearchFilters{MediaType: media_items.MediaTypePhoto},
+		Limit:   50,
+	}
+
+	var got []media_items.MediaItem
+	for {
+		page, nextPageToken, err := m.Search(ctx, options)
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		got = append(got, page...)
+		if nextPageToken == "" {
+			break
+		}
+		options.PageToken = nextPageToken
+	}
+	if 120 != len(got) {
+		t.Errorf("want: %d, got: %d", 120, len(got))
+	}
+
+	options.PageToken = mocks.PageTokenShouldFail
+	if _, _, err := m.Search(ctx, options); err == nil {
+		t.Errorf("error was expected, but not produced")
+	}
+}
+
+func newMediaItemsService(t *testing.T) (*media_items.Service, *mocks.MockedGooglePhotosService) {
+	t.Helper()
+	srv := mocks.NewMockedGooglePhotosService()
+	c := media_items.Config{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL(),
+	}
+	m, err := media_items.New(c)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	return m, srv
+}
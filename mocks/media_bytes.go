@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// fakeMediaSize is the size of the fake bytes of every media item.
+const fakeMediaSize = 4096
+
+// FakeMediaBytes returns the bytes served for the media item with the given base URL parameters.
+func FakeMediaBytes(mediaItemId string, params string) []byte {
+	pattern := []byte(fmt.Sprintf("%s=%s;", mediaItemId, params))
+	return bytes.Repeat(pattern, fakeMediaSize/len(pattern)+1)[:fakeMediaSize]
+}
+
+// mediaBaseURL returns the base URL of the media item bytes, served by mediaBytes.
+func (ms *MockedGooglePhotosService) mediaBaseURL(mediaItemId string) string {
+	return ms.baseURL + "/media/" + mediaItemId
+}
+
+// withBaseURL returns a copy of the media item with a base URL served by the mock.
+func (ms *MockedGooglePhotosService) withBaseURL(item *photoslibrary.MediaItem) *photoslibrary.MediaItem {
+	res := *item
+	res.BaseUrl = ms.mediaBaseURL(item.Id)
+	return &res
+}
+
+// withBaseURLs returns a copy of the media items with base URLs served by the mock.
+func (ms *MockedGooglePhotosService) withBaseURLs(items []*photoslibrary.MediaItem) []*photoslibrary.MediaItem {
+	res := make([]*photoslibrary.MediaItem, len(items))
+	for i, item := range items {
+		res[i] = ms.withBaseURL(item)
+	}
+	return res
+}
+
+// mediaParamsRe matches the base URL parameters accepted by mediaBytes.
+var mediaParamsRe = regexp.MustCompile(`^(d|dv|(w\d+|h\d+|c)(-(w\d+|h\d+|c))*)$`)
+
+// mediaBytes serves the bytes of a media item, as the base URLs do.
+// - Media item with Id == ShouldMakeAPIFailMediaItem will respond http.StatusInternalServerError.
+// - Unknown parameters will respond http.StatusBadRequest.
+// - Media items not found will respond http.StatusNotFound.
+// - Any other case serves FakeMediaBytes, supporting Range requests.
+func (ms *MockedGooglePhotosService) mediaBytes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mediaItemId, params, _ := strings.Cut(chi.URLParam(r, "mediaItem"), "=")
+
+	if ShouldMakeAPIFailMediaItem == mediaItemId {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !mediaParamsRe.MatchString(params) {
+		http.Error(w, "invalid parameters", http.StatusBadRequest)
+		return
+	}
+
+	if _, found := findMediaItemById(mediaItemId); !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	http.ServeContent(w, r, mediaItemId, time.Time{}, bytes.NewReader(FakeMediaBytes(mediaItemId, params)))
+}
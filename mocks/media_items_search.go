@@ -0,0 +1,180 @@
+package mocks
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// searchMediaItemsRequest is the request of the 'mediaItems.search' method.
+type searchMediaItemsRequest struct {
+	AlbumId   string         `json:"albumId"`
+	PageSize  int64          `json:"pageSize"`
+	PageToken string         `json:"pageToken"`
+	Filters   *searchFilters `json:"filters"`
+	OrderBy   string         `json:"orderBy"`
+}
+
+type searchFilters struct {
+	ContentFilter   *photoslibrary.ContentFilter   `json:"contentFilter"`
+	DateFilter      *photoslibrary.DateFilter      `json:"dateFilter"`
+	MediaTypeFilter *photoslibrary.MediaTypeFilter `json:"mediaTypeFilter"`
+	FeatureFilter   *struct {
+		IncludedFeatures []string `json:"includedFeatures"`
+	} `json:"featureFilter"`
+	IncludeArchivedMedia     bool `json:"includeArchivedMedia"`
+	ExcludeNonAppCreatedData bool `json:"excludeNonAppCreatedData"`
+}
+
+func (req *searchMediaItemsRequest) validate() error {
+	if req.AlbumId != "" && req.Filters != nil {
+		return errors.New("albumId and filters can't be set together")
+	}
+	if req.OrderBy != "" {
+		if req.Filters == nil || req.Filters.DateFilter == nil {
+			return errors.New("orderBy requires a dateFilter")
+		}
+		if req.OrderBy != "MediaMetadata.creation_time" && req.OrderBy != "MediaMetadata.creation_time desc" {
+			return errors.New("invalid orderBy")
+		}
+	}
+	if req.Filters != nil && req.Filters.MediaTypeFilter != nil && len(req.Filters.MediaTypeFilter.MediaTypes) > 1 {
+		return errors.New("only one media type is allowed")
+	}
+	return nil
+}
+
+// fakeContentCategories are the content categories of the fake media items.
+var fakeContentCategories = []string{"LANDSCAPES", "PETS", "FOOD"}
+
+// fakeIndex returns the index used to generate a fake media item.
+func fakeIndex(item *photoslibrary.MediaItem) int {
+	i, _ := strconv.Atoi(item.Id[strings.LastIndex(item.Id, "-")+1:])
+	return i
+}
+
+// fakeContentCategory returns the content category of a fake media item.
+func fakeContentCategory(item *photoslibrary.MediaItem) string {
+	return fakeContentCategories[fakeIndex(item)%len(fakeContentCategories)]
+}
+
+// isFakeFavorite returns true if the fake media item is a favorite.
+func isFakeFavorite(item *photoslibrary.MediaItem) bool {
+	return fakeIndex(item)%10 == 0
+}
+
+// isFakeAppCreated returns true if the fake media item was created by this app.
+func isFakeAppCreated(item *photoslibrary.MediaItem) bool {
+	return fakeIndex(item)%2 == 0
+}
+
+// searchFakeMediaItems returns the fake media items matching the filters, sorted as requested.
+func searchFakeMediaItems(filters *searchFilters, orderBy string) []*photoslibrary.MediaItem {
+	candidates := getFakeMediaItems(AvailableMediaItems)
+	if filters != nil && filters.IncludeArchivedMedia {
+		candidates = append(candidates, getFakeArchivedMediaItems()...)
+	}
+
+	var result []*photoslibrary.MediaItem
+	for _, item := range candidates {
+		if filters.match(item) {
+			result = append(result, item)
+		}
+	}
+
+	switch orderBy {
+	case "MediaMetadata.creation_time":
+		slices.SortStableFunc(result, func(a, b *photoslibrary.MediaItem) int {
+			return strings.Compare(a.MediaMetadata.CreationTime, b.MediaMetadata.CreationTime)
+		})
+	case "MediaMetadata.creation_time desc":
+		slices.SortStableFunc(result, func(a, b *photoslibrary.MediaItem) int {
+			return strings.Compare(b.MediaMetadata.CreationTime, a.MediaMetadata.CreationTime)
+		})
+	}
+	return result
+}
+
+// match returns true if the media item matches all the filters.
+func (f *searchFilters) match(item *photoslibrary.MediaItem) bool {
+	if f == nil {
+		return true
+	}
+	if f.ExcludeNonAppCreatedData && !isFakeAppCreated(item) {
+		return false
+	}
+	if f.MediaTypeFilter != nil && len(f.MediaTypeFilter.MediaTypes) == 1 {
+		isVideo := strings.HasPrefix(item.MimeType, "video/")
+		switch f.MediaTypeFilter.MediaTypes[0] {
+		case "PHOTO":
+			if isVideo {
+				return false
+			}
+		case "VIDEO":
+			if !isVideo {
+				return false
+			}
+		}
+	}
+	if f.FeatureFilter != nil && slices.Contains(f.FeatureFilter.IncludedFeatures, "FAVORITES") && !isFakeFavorite(item) {
+		return false
+	}
+	if f.ContentFilter != nil {
+		category := fakeContentCategory(item)
+		if len(f.ContentFilter.IncludedContentCategories) > 0 && !slices.Contains(f.ContentFilter.IncludedContentCategories, category) {
+			return false
+		}
+		if slices.Contains(f.ContentFilter.ExcludedContentCategories, category) {
+			return false
+		}
+	}
+	if f.DateFilter != nil {
+		created, err := time.Parse(time.RFC3339Nano, item.MediaMetadata.CreationTime)
+		if err != nil {
+			return false
+		}
+		return matchDateFilter(f.DateFilter, created)
+	}
+	return true
+}
+
+// matchDateFilter returns true if t matches any of the dates or date ranges.
+func matchDateFilter(f *photoslibrary.DateFilter, t time.Time) bool {
+	for _, d := range f.Dates {
+		if compareDate(d, t) == 0 {
+			return true
+		}
+	}
+	for _, r := range f.Ranges {
+		if compareDate(r.StartDate, t) <= 0 && compareDate(r.EndDate, t) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// compareDate compares t with the date d, ignoring the date fields that are not set.
+// It returns -1 if d is before t, 0 if they match and +1 if d is after t.
+func compareDate(d *photoslibrary.Date, t time.Time) int {
+	fields := [][2]int64{
+		{d.Year, int64(t.Year())},
+		{d.Month, int64(t.Month())},
+		{d.Day, int64(t.Day())},
+	}
+	for _, f := range fields {
+		if f[0] == 0 {
+			continue
+		}
+		if f[0] < f[1] {
+			return -1
+		}
+		if f[0] > f[1] {
+			return 1
+		}
+	}
+	return 0
+}
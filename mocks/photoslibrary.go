@@ -4,10 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"html"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 
@@ -18,15 +23,25 @@ const (
 	// ShouldMakeAPIFailMediaItem will make API fail.
 	ShouldMakeAPIFailMediaItem = "should-make-API-fail"
 
-	// ShouldReturnEmptyMediaItem will return an empty media item.
+	// PermissionDeniedMediaItem is a media item ID that the app is not allowed to access.
+	PermissionDeniedMediaItem = "fooPermissionDeniedId"
+
+	// ShouldReturnEmptyMediaItem will return an empty media item with an UNKNOWN status.
 	ShouldReturnEmptyMediaItem = "should-return-empty-media-item"
 
+	// ShouldReturnNoResult used as upload token truncates the batch create results before it.
+	ShouldReturnNoResult = "should-return-no-result"
+
 	// AlbumShouldFail used as album ID or Title to make the album service fail.
 	AlbumShouldFail = "should-fail"
 
 	// UploadShouldFail used as X-Goog-Upload-Name to make the upload service fai.
 	UploadShouldFail = "upload-should-fail"
 
+	// UploadShouldFailChunk used as X-Goog-Upload-Name makes the second chunk of a
+	// resumable upload fail after receiving half of it.
+	UploadShouldFailChunk = "upload-should-fail-chunk.mp4"
+
 	// UploadToken is sent when the upload was successful.
 	UploadToken = "valid-upload-token"
 
@@ -38,6 +53,16 @@ const (
 
 	// PageTokenShouldFail makes fail a paginated call.
 	PageTokenShouldFail = "should-fail"
+
+	// ExistingShareToken is the share token of an album shared by another user.
+	// It can be joined and left.
+	ExistingShareToken = "fooShareToken"
+
+	// ShareTokenShouldFail used as share token makes the shared albums service fail.
+	ShareTokenShouldFail = "should-fail"
+
+	// AlbumShouldReturnNoShareInfo used as album ID makes the share response have no share info.
+	AlbumShouldReturnNoShareInfo = "should-return-no-share-info"
 )
 
 const (
@@ -54,6 +79,11 @@ const (
 	// mentioned cases.
 	// @see: https://github.com/grpc/grpc-go/blob/master/codes/codes.go
 	grpcUnknownCode = 2
+	// NotFound means some requested entity was not found.
+	grpcNotFoundCode = 5
+	// PermissionDenied indicates the caller does not have permission to
+	// execute the specified operation.
+	grpcPermissionDeniedCode = 7
 
 	// maxMediaItemsPerPage is the maximum number of media items to request from the PhotosLibrary. Fewer media items
 	// might be returned than the specified number.
@@ -65,8 +95,14 @@ const (
 	// See https://developers.google.com/photos/library/guides/list#pagination
 	maxAlbumsPerPage = 50
 
+	// maxMediaItemsPerBatch is the maximum number of media items to add or remove from an album in a single call.
+	// See https://developers.google.com/photos/library/reference/rest/v1/albums/batchRemoveMediaItems
+	maxMediaItemsPerBatch = 50
+
 	// AvailableMediaItems is the number of media items in the fake collection. It should be bigger than `maxItemsPerPage`.
 	AvailableMediaItems = 150
+	// ArchivedMediaItems is the number of archived media items in the fake collection.
+	ArchivedMediaItems = 10
 	// AvailableAlbums is the number of media items in the fake collection. It should be bigger than `maxItemsPerPage`.
 	AvailableAlbums = 75
 )
@@ -85,22 +121,61 @@ var (
 type MockedGooglePhotosService struct {
 	server  *httptest.Server
 	baseURL string
+
+	mu sync.Mutex
+	// enrichmentsCount is the number of enrichment items created.
+	enrichmentsCount int
+	// sharedAlbums maps a share token with the corresponding shared album.
+	sharedAlbums map[string]*sharedAlbum
+	// albumContents maps an album ID with the IDs of the media items in it.
+	// Albums not present in the map contain all the fake media items.
+	albumContents map[string][]string
+	// uploadSessions maps an upload ID with the corresponding resumable upload session.
+	uploadSessions map[string]*uploadSession
 }
 
 // NewMockedGooglePhotosService returns a mocked Google Photos service.
 func NewMockedGooglePhotosService() *MockedGooglePhotosService {
-	ms := &MockedGooglePhotosService{}
+	ms := &MockedGooglePhotosService{
+		albumContents:  make(map[string][]string),
+		uploadSessions: make(map[string]*uploadSession),
+		sharedAlbums: map[string]*sharedAlbum{
+			ExistingShareToken: {
+				albumId: "fooId-1",
+				shareInfo: &photoslibrary.ShareInfo{
+					ShareToken:         ExistingShareToken,
+					ShareableUrl:       "https://photos.app.goo.gl/" + ExistingShareToken,
+					SharedAlbumOptions: &photoslibrary.SharedAlbumOptions{},
+				},
+			},
+		},
+	}
 	router := chi.NewRouter()
 	// Albums methods
 	router.Get("/v1/albums", ms.albumsList)
 	router.Post("/v1/albums", ms.albumsCreate)
 	router.Get("/v1/albums/{albumId}", ms.albumsGet)
+	router.Patch("/v1/albums/{albumId}", ms.albumsPatch)
 	router.Post("/v1/albums/{albumId}:batchAddMediaItems", ms.albumsBatchAddMediaItems)
+	router.Post("/v1/albums/{albumId}:batchRemoveMediaItems", ms.albumsBatchRemoveMediaItems)
+	router.Post("/v1/albums/{albumId}:addEnrichment", ms.albumsAddEnrichment)
+	router.Post("/v1/albums/{albumId}:share", ms.albumsShare)
+	router.Post("/v1/albums/{albumId}:unshare", ms.albumsUnshare)
+	// SharedAlbums methods
+	router.Get("/v1/sharedAlbums", ms.sharedAlbumsList)
+	router.Get("/v1/sharedAlbums/{shareToken}", ms.sharedAlbumsGet)
+	router.Post("/v1/sharedAlbums:join", ms.sharedAlbumsJoin)
+	router.Post("/v1/sharedAlbums:leave", ms.sharedAlbumsLeave)
 	// MediaItems methods
+	router.Get("/v1/mediaItems", ms.mediaItemsList)
 	router.Post("/v1/mediaItems:batchCreate", ms.mediaItemsBatchCreate)
 	router.Get("/v1/mediaItems/{mediaItemId}", ms.mediaItemsGet)
+	router.Get("/v1/mediaItems:batchGet", ms.mediaItemsBatchGet)
+	router.Patch("/v1/mediaItems/{mediaItemId}", ms.mediaItemsPatch)
 	router.Post("/v1/mediaItems:search", ms.mediaItemsSearch)
 	// Uploads methods
+	router.Get("/media/{mediaItem}", ms.mediaBytes)
+
 	router.Post("/v1/uploads", ms.handleUploads)
 	router.Post(ShouldResumeUpload, ms.handleResumeUpload)
 	router.Post("/v1/upload-session/upload-success", func(w http.ResponseWriter, r *http.Request) {
@@ -126,6 +201,26 @@ func (ms *MockedGooglePhotosService) URL() string {
 	return ms.baseURL
 }
 
+// AlbumMediaItems returns the IDs of the media items in the specified album.
+func (ms *MockedGooglePhotosService) AlbumMediaItems(albumId string) []string {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return slices.Clone(ms.albumContentsLocked(albumId))
+}
+
+// albumContentsLocked returns the IDs of the media items in the specified album.
+// It must be called with ms.mu held.
+func (ms *MockedGooglePhotosService) albumContentsLocked(albumId string) []string {
+	ids, ok := ms.albumContents[albumId]
+	if !ok {
+		for _, item := range getFakeMediaItems(AvailableMediaItems) {
+			ids = append(ids, item.Id)
+		}
+		ms.albumContents[albumId] = ids
+	}
+	return ids
+}
+
 var (
 	// ShouldFailAlbum is an album that will make the API fail.
 	ShouldFailAlbum = &photoslibrary.Album{
@@ -211,6 +306,67 @@ func (ms *MockedGooglePhotosService) albumsGet(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// albumsPatch implements 'albums.patch' method.
+// - Album with Id == ShouldFailAlbum.Id will respond http.StatusInternalServerError.
+// - Album with Id in AvailableAlbums will respond http.StatusOK with the updated album.
+// - Any other case will respond http.StatusNotFound.
+//
+// "flatPath": "v1/albums/{albumsId}",
+// "httpMethod": "PATCH",
+func (ms *MockedGooglePhotosService) albumsPatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	albumId := chi.URLParam(r, "albumId")
+
+	if albumId == ShouldFailAlbum.Id {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	album, found := findAlbumById(albumId)
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Title                 string `json:"title"`
+		CoverPhotoMediaItemId string `json:"coverPhotoMediaItemId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updateMask := r.URL.Query().Get("updateMask")
+	if updateMask == "" {
+		http.Error(w, "updateMask is required", http.StatusBadRequest)
+		return
+	}
+
+	res := *album
+	for _, field := range strings.Split(updateMask, ",") {
+		switch field {
+		case "title":
+			res.Title = req.Title
+		case "coverPhotoMediaItemId":
+			res.CoverPhotoBaseUrl = req.CoverPhotoMediaItemId + "BaseUrl"
+		default:
+			http.Error(w, fmt.Sprintf("invalid updateMask field: %s", sanitize(field)), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 // albumsList implements 'albums.list' method.
 //
 // "flatPath": "v1/albums",
@@ -313,6 +469,11 @@ func getFakeAlbums(numberOfItems int) []*photoslibrary.Album {
 }
 
 // albumsBatchAddMediaItems implements 'albums.batchAddMediaItems' method.
+// - Album with Id not in AvailableAlbums will respond http.StatusNotFound.
+// - Album with Id == ShouldFailAlbum.Id will respond http.StatusInternalServerError.
+// - Requests with more than 50 media items will respond http.StatusBadRequest.
+// - Requests with ShouldMakeAPIFailMediaItem will respond http.StatusInternalServerError.
+// - Any other case adds the media items to the album and responds http.StatusOK.
 //
 // "flatPath": "v1/albums/{albumsId}:batchAddMediaItems",
 // "httpMethod": "POST",
@@ -340,6 +501,11 @@ func (ms *MockedGooglePhotosService) albumsBatchAddMediaItems(w http.ResponseWri
 		return
 	}
 
+	if len(req.MediaItemIds) > maxMediaItemsPerBatch {
+		http.Error(w, "too many media items in the request", http.StatusBadRequest)
+		return
+	}
+
 	for _, mi := range req.MediaItemIds {
 		if ShouldMakeAPIFailMediaItem == mi {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -348,6 +514,16 @@ func (ms *MockedGooglePhotosService) albumsBatchAddMediaItems(w http.ResponseWri
 
 	}
 
+	ms.mu.Lock()
+	contents := ms.albumContentsLocked(albumId)
+	for _, mi := range req.MediaItemIds {
+		if !slices.Contains(contents, mi) {
+			contents = append(contents, mi)
+		}
+	}
+	ms.albumContents[albumId] = contents
+	ms.mu.Unlock()
+
 	w.WriteHeader(http.StatusOK)
 	res := photoslibrary.AlbumBatchAddMediaItemsResponse{}
 	if err := json.NewEncoder(w).Encode(res); err != nil {
@@ -356,6 +532,135 @@ func (ms *MockedGooglePhotosService) albumsBatchAddMediaItems(w http.ResponseWri
 	}
 }
 
+// albumsBatchRemoveMediaItems implements 'albums.batchRemoveMediaItems' method.
+// - Album with Id == ShouldFailAlbum.Id will respond http.StatusInternalServerError.
+// - Album with Id not in AvailableAlbums will respond http.StatusNotFound.
+// - Media items not present in the album will respond http.StatusBadRequest.
+// - Any other case removes the media items from the album and responds http.StatusOK.
+//
+// "flatPath": "v1/albums/{albumsId}:batchRemoveMediaItems",
+// "httpMethod": "POST",
+func (ms *MockedGooglePhotosService) albumsBatchRemoveMediaItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	albumId := chi.URLParam(r, "albumId")
+
+	if ShouldFailAlbum.Id == albumId {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if _, found := findAlbumById(albumId); !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		MediaItemIds []string `json:"mediaItemIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.MediaItemIds) > maxMediaItemsPerBatch {
+		http.Error(w, "too many media items in the request", http.StatusBadRequest)
+		return
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	contents := ms.albumContentsLocked(albumId)
+	for _, mi := range req.MediaItemIds {
+		if ShouldMakeAPIFailMediaItem == mi {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !slices.Contains(contents, mi) {
+			http.Error(w, fmt.Sprintf("media item %s is not in the album", sanitize(mi)), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ms.albumContents[albumId] = slices.DeleteFunc(slices.Clone(contents), func(id string) bool {
+		return slices.Contains(req.MediaItemIds, id)
+	})
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{}"))
+}
+
+// albumsAddEnrichment implements 'albums.addEnrichment' method.
+// - Album with Id == ShouldFailAlbum.Id will respond http.StatusInternalServerError.
+// - Album with Id not in AvailableAlbums will respond http.StatusNotFound.
+// - Requests without exactly one enrichment or without album position will respond http.StatusBadRequest.
+// - Any other case will respond http.StatusOK with the created enrichment item.
+//
+// "flatPath": "v1/albums/{albumsId}:addEnrichment",
+// "httpMethod": "POST",
+func (ms *MockedGooglePhotosService) albumsAddEnrichment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	albumId := chi.URLParam(r, "albumId")
+
+	if ShouldFailAlbum.Id == albumId {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if _, found := findAlbumById(albumId); !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var req photoslibrary.AddEnrichmentToAlbumRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.AlbumPosition == nil || req.NewEnrichmentItem == nil {
+		http.Error(w, "albumPosition and newEnrichmentItem are required", http.StatusBadRequest)
+		return
+	}
+
+	enrichments := 0
+	for _, present := range []bool{
+		req.NewEnrichmentItem.TextEnrichment != nil,
+		req.NewEnrichmentItem.LocationEnrichment != nil,
+		req.NewEnrichmentItem.MapEnrichment != nil,
+	} {
+		if present {
+			enrichments++
+		}
+	}
+	if enrichments != 1 {
+		http.Error(w, "exactly one enrichment is required", http.StatusBadRequest)
+		return
+	}
+
+	ms.mu.Lock()
+	ms.enrichmentsCount++
+	id := fmt.Sprintf("fooEnrichmentId-%d", ms.enrichmentsCount)
+	ms.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	res := photoslibrary.AddEnrichmentToAlbumResponse{
+		EnrichmentItem: &photoslibrary.EnrichmentItem{Id: id},
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 // findAlbumById returns if AvailableAlbums has an album with the specified Id.
 func findAlbumById(albumId string) (*photoslibrary.Album, bool) {
 	for _, a := range getFakeAlbums(AvailableAlbums) {
@@ -366,7 +671,25 @@ func findAlbumById(albumId string) (*photoslibrary.Album, bool) {
 	return &photoslibrary.Album{}, false
 }
 
-// albumsBatchRemoveMediaItems implements 'mediaItems.batchCreate' method.
+// batchCreateMediaItemsRequest is the request of the 'mediaItems.batchCreate' method.
+type batchCreateMediaItemsRequest struct {
+	AlbumId       string                       `json:"albumId"`
+	AlbumPosition *photoslibrary.AlbumPosition `json:"albumPosition"`
+	NewMediaItems []struct {
+		Description     string `json:"description"`
+		SimpleMediaItem struct {
+			FileName    string `json:"fileName"`
+			UploadToken string `json:"uploadToken"`
+		} `json:"simpleMediaItem"`
+	} `json:"newMediaItems"`
+}
+
+// mediaItemsBatchCreate implements 'mediaItems.batchCreate' method.
+// - Requests with more than maxMediaItemsPerBatch media items will respond http.StatusBadRequest.
+// - Any media item with ShouldMakeAPIFailMediaItem as upload token makes the whole request respond http.StatusInternalServerError.
+// - Results are truncated before the first media item with ShouldReturnNoResult as upload token.
+// - Media items with a file name or description keep them, otherwise they're derived from the upload token.
+// - Created media items are added to the album at the requested position. See insertAtPosition.
 //
 // "flatPath": "v1/mediaItems:batchCreate",
 // "httpMethod": "POST",
@@ -376,7 +699,7 @@ func (ms *MockedGooglePhotosService) mediaItemsBatchCreate(w http.ResponseWriter
 		return
 	}
 
-	var req photoslibrary.BatchCreateMediaItemsRequest
+	var req batchCreateMediaItemsRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -387,6 +710,11 @@ func (ms *MockedGooglePhotosService) mediaItemsBatchCreate(w http.ResponseWriter
 		return
 	}
 
+	if len(req.NewMediaItems) > maxMediaItemsPerBatch {
+		http.Error(w, "too many media items", http.StatusBadRequest)
+		return
+	}
+
 	newMediaItems := make([]*photoslibrary.NewMediaItemResult, len(req.NewMediaItems))
 	for i, item := range req.NewMediaItems {
 		if ShouldMakeAPIFailMediaItem == item.SimpleMediaItem.UploadToken {
@@ -395,15 +723,25 @@ func (ms *MockedGooglePhotosService) mediaItemsBatchCreate(w http.ResponseWriter
 		}
 		if ShouldReturnEmptyMediaItem == item.SimpleMediaItem.UploadToken {
 			newMediaItems[i] = &photoslibrary.NewMediaItemResult{
-				Status: &photoslibrary.Status{Code: grpcUnknownCode},
+				UploadToken: item.SimpleMediaItem.UploadToken,
+				Status:      &photoslibrary.Status{Code: grpcUnknownCode, Message: "fake unknown error"},
 			}
 		} else {
+			description := item.Description
+			if description == "" {
+				description = item.SimpleMediaItem.UploadToken + "Description"
+			}
+			filename := item.SimpleMediaItem.FileName
+			if filename == "" {
+				filename = item.SimpleMediaItem.UploadToken + "Filename"
+			}
 			newMediaItems[i] = &photoslibrary.NewMediaItemResult{
-				Status: &photoslibrary.Status{Code: grpcOKCode},
+				UploadToken: item.SimpleMediaItem.UploadToken,
+				Status:      &photoslibrary.Status{Code: grpcOKCode},
 				MediaItem: &photoslibrary.MediaItem{
 					BaseUrl:     item.SimpleMediaItem.UploadToken + "BaseUrl",
-					Description: item.SimpleMediaItem.UploadToken + "Description",
-					Filename:    item.SimpleMediaItem.UploadToken + "Filename",
+					Description: description,
+					Filename:    filename,
 					Id:          item.SimpleMediaItem.UploadToken + "Id",
 					ProductUrl:  item.SimpleMediaItem.UploadToken + "ProductUrl",
 					MediaMetadata: &photoslibrary.MediaMetadata{
@@ -416,6 +754,35 @@ func (ms *MockedGooglePhotosService) mediaItemsBatchCreate(w http.ResponseWriter
 		}
 	}
 
+	for i, item := range req.NewMediaItems {
+		if ShouldReturnNoResult == item.SimpleMediaItem.UploadToken {
+			newMediaItems = newMediaItems[:i]
+			break
+		}
+	}
+
+	if req.AlbumId != "" {
+		var ids []string
+		for _, res := range newMediaItems {
+			if res.MediaItem != nil {
+				ids = append(ids, res.MediaItem.Id)
+			}
+		}
+		ms.mu.Lock()
+		contents, err := insertAtPosition(ms.albumContentsLocked(req.AlbumId), ids, req.AlbumPosition)
+		if err == nil {
+			ms.albumContents[req.AlbumId] = contents
+		}
+		ms.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else if req.AlbumPosition != nil {
+		http.Error(w, "albumPosition requires an albumId", http.StatusBadRequest)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	res := photoslibrary.BatchCreateMediaItemsResponse{
 		NewMediaItemResults: newMediaItems,
@@ -450,7 +817,7 @@ func (ms *MockedGooglePhotosService) mediaItemsGet(w http.ResponseWriter, r *htt
 	}
 
 	w.WriteHeader(http.StatusOK)
-	res := mediaItem
+	res := ms.withBaseURL(mediaItem)
 	if err := json.NewEncoder(w).Encode(res); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -458,6 +825,10 @@ func (ms *MockedGooglePhotosService) mediaItemsGet(w http.ResponseWriter, r *htt
 }
 
 // mediaItemsSearch implements 'mediaItems.search' method.
+// - Album with Id == ShouldFailAlbum.Id will respond http.StatusInternalServerError.
+// - Requests with album ID and filters, or sorted without a date filter will respond http.StatusBadRequest.
+// - Requests with album ID return the media items in the album.
+// - Any other case returns the fake media items matching the filters. See getFakeMediaItems.
 //
 // "flatPath": "v1/mediaItems:search",
 // "httpMethod": "POST",
@@ -467,7 +838,7 @@ func (ms *MockedGooglePhotosService) mediaItemsSearch(w http.ResponseWriter, r *
 		return
 	}
 
-	var req photoslibrary.SearchMediaItemsRequest
+	var req searchMediaItemsRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -481,7 +852,17 @@ func (ms *MockedGooglePhotosService) mediaItemsSearch(w http.ResponseWriter, r *
 		return
 	}
 
-	mediaItems := getFakeMediaItems(AvailableMediaItems)
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var mediaItems []*photoslibrary.MediaItem
+	if albumId != "" {
+		mediaItems = ms.albumMediaItems(albumId)
+	} else {
+		mediaItems = searchFakeMediaItems(req.Filters, req.OrderBy)
+	}
 
 	p := newMediaItemsPaginator(pageSize, mediaItems)
 
@@ -494,7 +875,7 @@ func (ms *MockedGooglePhotosService) mediaItemsSearch(w http.ResponseWriter, r *
 
 	w.WriteHeader(http.StatusOK)
 	res := photoslibrary.SearchMediaItemsResponse{
-		MediaItems:    items,
+		MediaItems:    ms.withBaseURLs(items),
 		NextPageToken: nextPageToken,
 	}
 	if err := json.NewEncoder(w).Encode(res); err != nil {
@@ -535,6 +916,196 @@ func (p *mediaItemsPaginator) page(pageToken string) (results []*photoslibrary.M
 	return p.items[pageStartAt:pageEndsAt], nextPageToken
 }
 
+// insertAtPosition returns the album contents with ids inserted at the specified position.
+// Enrichment items are not kept in the album contents, so media items placed after
+// an enrichment item are added to the end of the album.
+func insertAtPosition(contents []string, ids []string, position *photoslibrary.AlbumPosition) ([]string, error) {
+	if position == nil {
+		return append(slices.Clone(contents), ids...), nil
+	}
+	switch position.Position {
+	case "", "LAST_IN_ALBUM", "AFTER_ENRICHMENT_ITEM":
+		return append(slices.Clone(contents), ids...), nil
+	case "FIRST_IN_ALBUM":
+		return slices.Insert(slices.Clone(contents), 0, ids...), nil
+	case "AFTER_MEDIA_ITEM":
+		i := slices.Index(contents, position.RelativeMediaItemId)
+		if i < 0 {
+			return nil, fmt.Errorf("media item %s not found in album", position.RelativeMediaItemId)
+		}
+		return slices.Insert(slices.Clone(contents), i+1, ids...), nil
+	default:
+		return nil, fmt.Errorf("unknown position %s", position.Position)
+	}
+}
+
+// albumMediaItems returns the media items in the specified album.
+func (ms *MockedGooglePhotosService) albumMediaItems(albumId string) []*photoslibrary.MediaItem {
+	ids := ms.AlbumMediaItems(albumId)
+	mediaItems := make([]*photoslibrary.MediaItem, len(ids))
+	for i, id := range ids {
+		item, found := findMediaItemById(id)
+		if !found {
+			item = &photoslibrary.MediaItem{Id: id}
+		}
+		mediaItems[i] = item
+	}
+	return mediaItems
+}
+
+// mediaItemsList implements 'mediaItems.list' method.
+// - Page token == PageTokenShouldFail will respond http.StatusInternalServerError.
+// - Any other case returns the fake media items. See getFakeMediaItems.
+//
+// "flatPath": "v1/mediaItems",
+// "httpMethod": "GET",
+func (ms *MockedGooglePhotosService) mediaItemsList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pageSize, pageToken := ms.paginationOptions(r, maxMediaItemsPerPage)
+	p := newMediaItemsPaginator(pageSize, getFakeMediaItems(AvailableMediaItems))
+
+	if PageTokenShouldFail == pageToken {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	items, nextPageToken := p.page(pageToken)
+
+	w.WriteHeader(http.StatusOK)
+	res := struct {
+		MediaItems    []*photoslibrary.MediaItem `json:"mediaItems"`
+		NextPageToken string                     `json:"nextPageToken,omitempty"`
+	}{ms.withBaseURLs(items), nextPageToken}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// mediaItemsPatch implements 'mediaItems.patch' method.
+// - Media item with Id == ShouldMakeAPIFailMediaItem will respond http.StatusInternalServerError.
+// - Media items not created by this app will respond http.StatusForbidden. See isFakeAppCreated.
+// - PermissionDeniedMediaItem will respond http.StatusForbidden, because of insufficient scopes.
+// - Descriptions of 1000 characters or more will respond http.StatusBadRequest.
+// - Media items not found will respond http.StatusNotFound.
+//
+// "flatPath": "v1/mediaItems/{mediaItemsId}",
+// "httpMethod": "PATCH",
+func (ms *MockedGooglePhotosService) mediaItemsPatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mediaItemId := chi.URLParam(r, "mediaItemId")
+
+	if ShouldMakeAPIFailMediaItem == mediaItemId {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if PermissionDeniedMediaItem == mediaItemId {
+		writeAPIError(w, http.StatusForbidden, "PERMISSION_DENIED", "Request had insufficient authentication scopes.")
+		return
+	}
+
+	mediaItem, found := findMediaItemById(mediaItemId)
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !isFakeAppCreated(mediaItem) {
+		writeAPIError(w, http.StatusForbidden, "PERMISSION_DENIED", "Only media items created by the app can be updated, and this one is not app-created.")
+		return
+	}
+
+	var req struct {
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Get("updateMask") != "description" {
+		http.Error(w, "invalid updateMask", http.StatusBadRequest)
+		return
+	}
+
+	if utf8.RuneCountInString(req.Description) >= 1000 {
+		http.Error(w, "description is too long", http.StatusBadRequest)
+		return
+	}
+
+	res := *mediaItem
+	res.Description = req.Description
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// mediaItemsBatchGet implements 'mediaItems.batchGet' method.
+// - Requests with more than maxMediaItemsPerBatch media items will respond http.StatusBadRequest.
+// - Any ShouldMakeAPIFailMediaItem media item ID makes the whole request respond http.StatusInternalServerError.
+// - PermissionDeniedMediaItem returns a PERMISSION_DENIED status.
+// - Media items not found return a NOT_FOUND status.
+//
+// "flatPath": "v1/mediaItems:batchGet",
+// "httpMethod": "GET",
+func (ms *MockedGooglePhotosService) mediaItemsBatchGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ids := r.URL.Query()["mediaItemIds"]
+	if len(ids) == 0 || len(ids) > maxMediaItemsPerBatch {
+		http.Error(w, "invalid number of media items", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]*mediaItemResult, len(ids))
+	for i, id := range ids {
+		if ShouldMakeAPIFailMediaItem == id {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if PermissionDeniedMediaItem == id {
+			results[i] = &mediaItemResult{Status: &photoslibrary.Status{Code: grpcPermissionDeniedCode, Message: "fake permission denied"}}
+			continue
+		}
+		mediaItem, found := findMediaItemById(id)
+		if !found {
+			results[i] = &mediaItemResult{Status: &photoslibrary.Status{Code: grpcNotFoundCode, Message: "fake not found"}}
+			continue
+		}
+		results[i] = &mediaItemResult{MediaItem: ms.withBaseURL(mediaItem)}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	res := struct {
+		MediaItemResults []*mediaItemResult `json:"mediaItemResults"`
+	}{results}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// mediaItemResult is the result of every media item in the 'mediaItems.batchGet' method.
+type mediaItemResult struct {
+	MediaItem *photoslibrary.MediaItem `json:"mediaItem,omitempty"`
+	Status    *photoslibrary.Status    `json:"status,omitempty"`
+}
+
 // findMediaItemById returns if fake mediaItems collection has a media item with the specified Id.
 func findMediaItemById(mediaItemId string) (*photoslibrary.MediaItem, bool) {
 	for _, a := range getFakeMediaItems(AvailableMediaItems) {
@@ -542,35 +1113,96 @@ func findMediaItemById(mediaItemId string) (*photoslibrary.MediaItem, bool) {
 			return a, true
 		}
 	}
+	for _, a := range getFakeArchivedMediaItems() {
+		if mediaItemId == a.Id {
+			return a, true
+		}
+	}
 	return &photoslibrary.MediaItem{}, false
 }
 
+// fakeCreationTime is the creation time of the first fake media item.
+// Every next media item has been created one day later.
+var fakeCreationTime = time.Date(2014, time.October, 2, 15, 1, 23, 45123456, time.UTC)
+
 // getFakeMediaItems returns a collection of MediaItems with the specified number of it.
+// Media items are sorted by creation time, and:
+//   - Every 5th media item is a video, the rest are photos. Both with camera metadata.
+//   - Every 7th media item has been added by a contributor.
+//   - Content categories are LANDSCAPES, PETS and FOOD in turns. See fakeContentCategory.
+//   - Every 10th media item is a favorite. See isFakeFavorite.
+//   - Odd media items were not created by this app. See isFakeAppCreated.
 func getFakeMediaItems(numberOfItems int64) []*photoslibrary.MediaItem {
 	mediaItemsResult := make([]*photoslibrary.MediaItem, numberOfItems)
 	for i := int64(0); i < numberOfItems; i++ {
-		mediaItemsResult[i] = &photoslibrary.MediaItem{
-			BaseUrl:     fmt.Sprintf("fooBaseUrl-%d", i),
-			Description: fmt.Sprintf("fooDescription-%d", i),
-			Filename:    fmt.Sprintf("fooFilename-%d", i),
-			Id:          fmt.Sprintf("fooId-%d", i),
-			ProductUrl:  fmt.Sprintf("fooProductUrl-%d", i),
-			MediaMetadata: &photoslibrary.MediaMetadata{
-				CreationTime: "2014-10-02T15:01:23.045123456Z",
-				Height:       800,
-				Width:        600,
-			},
-		}
+		mediaItemsResult[i] = newFakeMediaItem(fmt.Sprintf("fooId-%d", i), i)
+	}
+	return mediaItemsResult
+}
+
+// getFakeArchivedMediaItems returns the archived media items.
+// They are only returned when searching with 'includeArchivedMedia'.
+func getFakeArchivedMediaItems() []*photoslibrary.MediaItem {
+	mediaItemsResult := make([]*photoslibrary.MediaItem, ArchivedMediaItems)
+	for i := int64(0); i < ArchivedMediaItems; i++ {
+		mediaItemsResult[i] = newFakeMediaItem(fmt.Sprintf("fooArchivedId-%d", i), i)
 	}
 	return mediaItemsResult
 }
 
+func newFakeMediaItem(id string, i int64) *photoslibrary.MediaItem {
+	item := &photoslibrary.MediaItem{
+		BaseUrl:     fmt.Sprintf("fooBaseUrl-%d", i),
+		Description: fmt.Sprintf("fooDescription-%d", i),
+		Filename:    fmt.Sprintf("fooFilename-%d", i),
+		Id:          id,
+		MimeType:    "image/jpeg",
+		ProductUrl:  fmt.Sprintf("fooProductUrl-%d", i),
+		MediaMetadata: &photoslibrary.MediaMetadata{
+			CreationTime: fakeCreationTime.AddDate(0, 0, int(i)).Format(time.RFC3339Nano),
+			Height:       800,
+			Width:        600,
+		},
+	}
+	if i%5 == 4 {
+		item.MimeType = "video/mp4"
+		item.MediaMetadata.Video = &photoslibrary.Video{
+			CameraMake:  "fooCameraMake",
+			CameraModel: "fooCameraModel",
+			Fps:         30,
+			Status:      "READY",
+		}
+	} else {
+		item.MediaMetadata.Photo = &photoslibrary.Photo{
+			ApertureFNumber: 1.8,
+			CameraMake:      "fooCameraMake",
+			CameraModel:     "fooCameraModel",
+			ExposureTime:    "0.008s",
+			FocalLength:     4.25,
+			IsoEquivalent:   100,
+		}
+	}
+	if i%7 == 6 {
+		item.ContributorInfo = &photoslibrary.ContributorInfo{
+			DisplayName:           fmt.Sprintf("fooContributor-%d", i),
+			ProfilePictureBaseUrl: fmt.Sprintf("fooProfilePictureBaseUrl-%d", i),
+		}
+	}
+	return item
+}
+
 func (ms *MockedGooglePhotosService) handleUploads(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	// Uploads must send the MIME type of a photo or a video.
+	if ct := r.Header.Get("X-Goog-Upload-Content-Type"); !strings.HasPrefix(ct, "image/") && !strings.HasPrefix(ct, "video/") {
+		http.Error(w, "invalid X-Goog-Upload-Content-Type", http.StatusBadRequest)
+		return
+	}
+
 	if r.Header.Get("X-Goog-Upload-Protocol") == "resumable" {
 		ms.handleStartUpload(w, r)
 		return
@@ -585,14 +1217,13 @@ func (ms *MockedGooglePhotosService) handleSimpleUpload(w http.ResponseWriter, r
 		return
 	}
 
-	var bodyContent []byte
-	bodyLength, err := r.Body.Read(bodyContent)
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	expectedLength, _ := strconv.Atoi(r.Header.Get("Content-Length"))
-	if expectedLength != bodyLength {
+	// Content-Length is unknown (-1) in chunked requests.
+	if r.ContentLength >= 0 && r.ContentLength != int64(len(body)) {
 		http.Error(w, "different length", http.StatusBadRequest)
 		return
 	}
@@ -601,44 +1232,17 @@ func (ms *MockedGooglePhotosService) handleSimpleUpload(w http.ResponseWriter, r
 	_, _ = w.Write([]byte(UploadToken))
 }
 
-func (ms *MockedGooglePhotosService) handleStartUpload(w http.ResponseWriter, r *http.Request) {
-	if UploadShouldFail == r.Header.Get("X-Goog-Upload-File-Name") {
-		http.Error(w, "upload should fail", http.StatusInternalServerError)
-		return
-	}
-
-	if r.Header.Get("X-Goog-Upload-Command") != "start" {
-		command := sanitize(r.Header.Get("X-Goog-Upload-Command"))
-		http.Error(w, fmt.Sprintf("unexpected upload command: %s", command), http.StatusBadRequest)
-		return
-	}
-
-	// success: sent the URL to resume the upload
-	w.Header().Set("X-Goog-Upload-URL", ms.URL()+ShouldResumeUpload)
-}
-
-func (ms *MockedGooglePhotosService) handleResumeUpload(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	switch r.Header.Get("X-Goog-Upload-Command") {
-
-	case "query":
-		w.Header().Set("X-Goog-Upload-Status", "active")
-		w.Header().Set("X-Goog-Upload-Size-Received", "1000")
-		return
-
-	case "upload, finalize":
-		// success: response the upload token.
-		_, _ = w.Write([]byte(UploadToken))
-		return
-
-	default:
-		command := sanitize(r.Header.Get("X-Goog-Upload-Command"))
-		http.Error(w, fmt.Sprintf("unexpected upload command: %s", command), http.StatusBadRequest)
-	}
+// writeAPIError writes an error response of the API, like the ones of the Google APIs.
+func writeAPIError(w http.ResponseWriter, code int, status, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"status":  status,
+		},
+	})
 }
 
 func sanitize(input string) string {
@@ -0,0 +1,292 @@
+package mocks
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+// sharedAlbum holds the sharing state of an album.
+type sharedAlbum struct {
+	albumId   string
+	shareInfo *photoslibrary.ShareInfo
+	// owned is true when the album was shared by the user.
+	owned bool
+	// joined is true when the user has joined the album.
+	joined bool
+}
+
+// album returns the shared album including its share info.
+func (sa *sharedAlbum) album() *photoslibrary.Album {
+	a, _ := findAlbumById(sa.albumId)
+	album := *a
+	album.ShareInfo = sa.shareInfo
+	return &album
+}
+
+// findSharedAlbumByAlbumIdLocked returns the shared album for the specified album id.
+// It must be called with ms.mu held.
+func (ms *MockedGooglePhotosService) findSharedAlbumByAlbumIdLocked(albumId string) (*sharedAlbum, bool) {
+	for _, sa := range ms.sharedAlbums {
+		if sa.albumId == albumId {
+			return sa, true
+		}
+	}
+	return nil, false
+}
+
+// albumsShare implements 'albums.share' method.
+// - Album with Id == ShouldFailAlbum.Id will respond http.StatusInternalServerError.
+// - Album with Id == AlbumShouldReturnNoShareInfo will respond http.StatusOK without the share info.
+// - Album with Id not in AvailableAlbums will respond http.StatusNotFound.
+// - Any other case will respond http.StatusOK with the share info.
+//
+// "flatPath": "v1/albums/{albumsId}:share",
+// "httpMethod": "POST",
+func (ms *MockedGooglePhotosService) albumsShare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	albumId := chi.URLParam(r, "albumId")
+
+	if ShouldFailAlbum.Id == albumId {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if AlbumShouldReturnNoShareInfo == albumId {
+		_, _ = w.Write([]byte("{}"))
+		return
+	}
+
+	if _, found := findAlbumById(albumId); !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var req photoslibrary.ShareAlbumRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	options := req.SharedAlbumOptions
+	if options == nil {
+		options = &photoslibrary.SharedAlbumOptions{}
+	}
+
+	ms.mu.Lock()
+	sa, found := ms.findSharedAlbumByAlbumIdLocked(albumId)
+	if !found {
+		shareToken := albumId + "ShareToken"
+		sa = &sharedAlbum{
+			albumId: albumId,
+			shareInfo: &photoslibrary.ShareInfo{
+				ShareToken:   shareToken,
+				ShareableUrl: "https://photos.app.goo.gl/" + shareToken,
+			},
+			owned: true,
+		}
+		ms.sharedAlbums[shareToken] = sa
+	}
+	sa.shareInfo.SharedAlbumOptions = options
+	res := photoslibrary.ShareAlbumResponse{
+		ShareInfo: sa.shareInfo,
+	}
+	ms.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// albumsUnshare implements 'albums.unshare' method.
+// - Album with Id == ShouldFailAlbum.Id will respond http.StatusInternalServerError.
+// - Album with Id not in AvailableAlbums will respond http.StatusNotFound.
+// - Any other case will respond http.StatusOK, removing the album sharing, if any.
+//
+// "flatPath": "v1/albums/{albumsId}:unshare",
+// "httpMethod": "POST",
+func (ms *MockedGooglePhotosService) albumsUnshare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	albumId := chi.URLParam(r, "albumId")
+
+	if ShouldFailAlbum.Id == albumId {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if _, found := findAlbumById(albumId); !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ms.mu.Lock()
+	if sa, found := ms.findSharedAlbumByAlbumIdLocked(albumId); found && sa.owned {
+		delete(ms.sharedAlbums, sa.shareInfo.ShareToken)
+	}
+	ms.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{}"))
+}
+
+// sharedAlbumsGet implements 'sharedAlbums.get' method.
+// - Share token == ShareTokenShouldFail will respond http.StatusInternalServerError.
+// - Unknown share tokens will respond http.StatusNotFound.
+// - Any other case will respond http.StatusOK with the shared album.
+//
+// "flatPath": "v1/sharedAlbums/{shareToken}",
+// "httpMethod": "GET",
+func (ms *MockedGooglePhotosService) sharedAlbumsGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	shareToken := chi.URLParam(r, "shareToken")
+
+	if ShareTokenShouldFail == shareToken {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ms.mu.Lock()
+	sa, found := ms.sharedAlbums[shareToken]
+	var res *photoslibrary.Album
+	if found {
+		res = sa.album()
+	}
+	ms.mu.Unlock()
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// sharedAlbumsList implements 'sharedAlbums.list' method.
+// It lists the albums shared by the user and the ones joined by the user.
+//
+// "flatPath": "v1/sharedAlbums",
+// "httpMethod": "GET",
+func (ms *MockedGooglePhotosService) sharedAlbumsList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pageSize, pageToken := ms.paginationOptions(r, maxAlbumsPerPage)
+
+	if PageTokenShouldFail == pageToken {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var albums []*photoslibrary.Album
+	ms.mu.Lock()
+	// Keep the same order as the fake albums, so pagination is stable.
+	for _, a := range getFakeAlbums(AvailableAlbums) {
+		if sa, found := ms.findSharedAlbumByAlbumIdLocked(a.Id); found && (sa.owned || sa.joined) {
+			albums = append(albums, sa.album())
+		}
+	}
+	ms.mu.Unlock()
+
+	p := newAlbumsPaginator(pageSize, albums)
+	items, nextPageToken := p.page(pageToken)
+
+	w.WriteHeader(http.StatusOK)
+	res := photoslibrary.ListSharedAlbumsResponse{
+		SharedAlbums:  items,
+		NextPageToken: nextPageToken,
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// sharedAlbumsJoin implements 'sharedAlbums.join' method.
+// - Share token == ShareTokenShouldFail will respond http.StatusInternalServerError.
+// - Unknown share tokens will respond http.StatusNotFound.
+// - Any other case will respond http.StatusOK with the joined album.
+//
+// "flatPath": "v1/sharedAlbums:join",
+// "httpMethod": "POST",
+func (ms *MockedGooglePhotosService) sharedAlbumsJoin(w http.ResponseWriter, r *http.Request) {
+	ms.setSharedAlbumJoined(w, r, true)
+}
+
+// sharedAlbumsLeave implements 'sharedAlbums.leave' method.
+// - Share token == ShareTokenShouldFail will respond http.StatusInternalServerError.
+// - Unknown share tokens will respond http.StatusNotFound.
+// - Share tokens of albums owned by the user will respond http.StatusBadRequest.
+// - Any other case will respond http.StatusOK.
+//
+// "flatPath": "v1/sharedAlbums:leave",
+// "httpMethod": "POST",
+func (ms *MockedGooglePhotosService) sharedAlbumsLeave(w http.ResponseWriter, r *http.Request) {
+	ms.setSharedAlbumJoined(w, r, false)
+}
+
+func (ms *MockedGooglePhotosService) setSharedAlbumJoined(w http.ResponseWriter, r *http.Request, joined bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ShareToken string `json:"shareToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if ShareTokenShouldFail == req.ShareToken {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ms.mu.Lock()
+	sa, found := ms.sharedAlbums[req.ShareToken]
+	if !found {
+		ms.mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if sa.owned && !joined {
+		ms.mu.Unlock()
+		http.Error(w, "the owner of the album can not leave it", http.StatusBadRequest)
+		return
+	}
+	sa.joined = joined
+	res := map[string]interface{}{}
+	if joined {
+		res["album"] = sa.album()
+	}
+	ms.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
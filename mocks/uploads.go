@@ -0,0 +1,132 @@
+package mocks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// uploadSession is a resumable upload session.
+type uploadSession struct {
+	size     int64 // X-Goog-Upload-Raw-Size sent when starting the upload.
+	received int64
+	final    bool
+
+	// failChunk makes the second chunk fail once. See UploadShouldFailChunk.
+	failChunk bool
+	chunks    int
+}
+
+// handleStartUpload implements the 'start' command of resumable uploads.
+// - Uploads with UploadShouldFail as file name will respond http.StatusInternalServerError.
+// - Any other case creates a new session, sending its URL in the X-Goog-Upload-URL header.
+func (ms *MockedGooglePhotosService) handleStartUpload(w http.ResponseWriter, r *http.Request) {
+	if UploadShouldFail == r.Header.Get("X-Goog-Upload-File-Name") {
+		http.Error(w, "upload should fail", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("X-Goog-Upload-Command") != "start" {
+		command := sanitize(r.Header.Get("X-Goog-Upload-Command"))
+		http.Error(w, fmt.Sprintf("unexpected upload command: %s", command), http.StatusBadRequest)
+		return
+	}
+
+	size, err := strconv.ParseInt(r.Header.Get("X-Goog-Upload-Raw-Size"), 10, 64)
+	if err != nil || size < 0 {
+		http.Error(w, "invalid X-Goog-Upload-Raw-Size", http.StatusBadRequest)
+		return
+	}
+
+	ms.mu.Lock()
+	id := strconv.Itoa(len(ms.uploadSessions) + 1)
+	ms.uploadSessions[id] = &uploadSession{
+		size:      size,
+		failChunk: UploadShouldFailChunk == r.Header.Get("X-Goog-Upload-File-Name"),
+	}
+	ms.mu.Unlock()
+
+	// success: sent the URL to resume the upload
+	w.Header().Set("X-Goog-Upload-Chunk-Granularity", "262144")
+	w.Header().Set("X-Goog-Upload-URL", ms.URL()+ShouldResumeUpload+"?upload_id="+id)
+}
+
+// handleResumeUpload implements the 'query', 'upload' and 'finalize' commands of resumable uploads.
+// - Unknown sessions will respond http.StatusNotFound.
+// - Bytes sent at an offset different from the received bytes will respond http.StatusBadRequest.
+// - Finalizing before receiving all the bytes will respond http.StatusBadRequest.
+// - The second chunk of sessions started with UploadShouldFailChunk as file name will receive
+// half of the bytes and respond http.StatusServiceUnavailable, once.
+func (ms *MockedGooglePhotosService) handleResumeUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	session, ok := ms.uploadSessions[r.URL.Query().Get("upload_id")]
+	if !ok {
+		http.Error(w, "upload session not found", http.StatusNotFound)
+		return
+	}
+
+	command := r.Header.Get("X-Goog-Upload-Command")
+	switch command {
+	case "query":
+		status := "active"
+		if session.final {
+			status = "final"
+		}
+		w.Header().Set("X-Goog-Upload-Status", status)
+		w.Header().Set("X-Goog-Upload-Size-Received", strconv.FormatInt(session.received, 10))
+		return
+
+	case "upload", "upload, finalize", "finalize":
+		if session.final {
+			http.Error(w, "upload is already finalized", http.StatusBadRequest)
+			return
+		}
+		offset, err := strconv.ParseInt(r.Header.Get("X-Goog-Upload-Offset"), 10, 64)
+		if err != nil || offset != session.received {
+			http.Error(w, fmt.Sprintf("unexpected offset, want: %d", session.received), http.StatusBadRequest)
+			return
+		}
+		session.chunks++
+		if session.failChunk && session.chunks == 2 {
+			session.failChunk = false
+			n, _ := io.CopyN(io.Discard, r.Body, r.ContentLength/2)
+			session.received += n
+			http.Error(w, "chunk failed", http.StatusServiceUnavailable)
+			return
+		}
+		n, err := io.Copy(io.Discard, r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Content-Length is unknown (-1) in chunked requests.
+		if r.ContentLength >= 0 && r.ContentLength != n {
+			http.Error(w, "different length", http.StatusBadRequest)
+			return
+		}
+		session.received += n
+		if command == "upload" {
+			return
+		}
+		if session.received != session.size {
+			http.Error(w, fmt.Sprintf("received %d bytes, want: %d", session.received, session.size), http.StatusBadRequest)
+			return
+		}
+		session.final = true
+
+		// success: response the upload token.
+		_, _ = w.Write([]byte(UploadToken))
+		return
+
+	default:
+		http.Error(w, fmt.Sprintf("unexpected upload command: %s", sanitize(command)), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,8 @@
+package shared_albums
+
+import "errors"
+
+var (
+	// ErrAlbumNotFound is the error returned when a shared album is not found.
+	ErrAlbumNotFound = errors.New("shared album not found")
+)
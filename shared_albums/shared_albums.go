@@ -0,0 +1,341 @@
+package shared_albums
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/library"
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+	"google.golang.org/api/googleapi"
+	"net/http"
+)
+
+// An Album represents a shared Google Photos album.
+//
+// See: https://developers.google.com/photos/library/guides/share-media.
+type Album struct {
+	// CoverPhotoBaseURL: [Output only] A BaseURL to the cover photo's bytes.
+	// This should not be used as is.
+	// Parameters should be appended to this BaseURL before use. For example,
+	// '=w2048-h1024' will set the dimensions of the cover photo to have a
+	// width of 2048 px and height of 1024 px.
+	CoverPhotoBaseURL string
+
+	// Id: [Output only] Identifier for the album. This is a persistent
+	// identifier that can be used to identify this album.
+	ID string
+
+	// IsWriteable: [Output only] True if media items can be created in the
+	// album.
+	IsWriteable bool
+
+	// ProductURL: [Output only] Google Photos BaseURL for the album. The user
+	// needs to be signed in to their Google Photos account to access this link.
+	ProductURL string
+
+	// ShareInfo: [Output only] Information related to shared albums.
+	// This field is only populated if the album is a shared album, the
+	// developer created the album and the user has granted the
+	// photoslibrary.sharing scope.
+	ShareInfo *ShareInfo
+
+	// Title: Name of the album displayed to the user in their Google Photos
+	// account.
+	Title string
+
+	// TotalMediaItems: [Output only] The number of media items in the album.
+	TotalMediaItems int64
+}
+
+// A ShareInfo represents the information related to a shared album.
+//
+// See: https://developers.google.com/photos/library/reference/rest/v1/albums#shareinfo.
+type ShareInfo struct {
+	// SharedAlbumOptions: Options that control the sharing of an album.
+	SharedAlbumOptions SharedAlbumOptions
+
+	// ShareableURL: A link to the album that's now shared on the Google Photos website and app.
+	// Anyone with the link can access this shared album and see all of the items present in the album.
+	ShareableURL string
+
+	// ShareToken: A token that can be used by other users to join this shared album via the API.
+	ShareToken string
+}
+
+// SharedAlbumOptions set the options to control the sharing of an album.
+type SharedAlbumOptions struct {
+	// IsCollaborative: True if the shared album allows collaborators (users who have joined
+	// the album) to add media items to it. Defaults to false.
+	IsCollaborative bool
+
+	// IsCommentable: True if the shared album allows the owner and the collaborators (users
+	// who have joined the album) to add comments to the album. Defaults to false.
+	IsCommentable bool
+}
+
+// Config holds the configuration parameters for the service.
+type Config struct {
+	// HTTP client used to communicate with the API.
+	Client *http.Client
+
+	// [Optional] Base URL for API requests.
+	// BaseURL should always be specified with a trailing slash.
+	BaseURL string
+
+	// [Optional] User agent used when communicating with the Google Photos API.
+	UserAgent string
+}
+
+// Service implements a shared albums Google Photos client.
+//
+// Sharing requires the photoslibrary.sharing scope.
+type Service struct {
+	photos PhotosLibraryClient
+}
+
+// PhotosLibraryClient represents a Google Photos client using `gphotosuploader/googlemirror/api/photoslibrary`.
+type PhotosLibraryClient interface {
+	Share(albumId string, shareAlbumRequest *photoslibrary.ShareAlbumRequest) *photoslibrary.AlbumsShareCall
+	Unshare(albumId string) *library.AlbumsUnshareCall
+	Join(joinSharedAlbumRequest *photoslibrary.JoinSharedAlbumRequest) *photoslibrary.SharedAlbumsJoinCall
+	Leave(leaveSharedAlbumRequest *library.LeaveSharedAlbumRequest) *library.SharedAlbumsLeaveCall
+	Get(shareToken string) *library.SharedAlbumsGetCall
+	List() *photoslibrary.SharedAlbumsListCall
+}
+
+// photosLibraryClient joins the sharing calls from `gphotosuploader/googlemirror/api/photoslibrary`
+// with the ones missing there.
+type photosLibraryClient struct {
+	albums       *photoslibrary.AlbumsService
+	sharedAlbums *photoslibrary.SharedAlbumsService
+	library      *library.Service
+}
+
+func (c photosLibraryClient) Share(albumId string, req *photoslibrary.ShareAlbumRequest) *photoslibrary.AlbumsShareCall {
+	return c.albums.Share(albumId, req)
+}
+
+func (c photosLibraryClient) Unshare(albumId string) *library.AlbumsUnshareCall {
+	return c.library.Albums.Unshare(albumId)
+}
+
+func (c photosLibraryClient) Join(req *photoslibrary.JoinSharedAlbumRequest) *photoslibrary.SharedAlbumsJoinCall {
+	return c.sharedAlbums.Join(req)
+}
+
+func (c photosLibraryClient) Leave(req *library.LeaveSharedAlbumRequest) *library.SharedAlbumsLeaveCall {
+	return c.library.SharedAlbums.Leave(req)
+}
+
+func (c photosLibraryClient) Get(shareToken string) *library.SharedAlbumsGetCall {
+	return c.library.SharedAlbums.Get(shareToken)
+}
+
+func (c photosLibraryClient) List() *photoslibrary.SharedAlbumsListCall {
+	return c.sharedAlbums.List()
+}
+
+// New returns a shared albums Google Photos service.
+func New(config Config) (*Service, error) {
+	s, err := photoslibrary.New(config.Client)
+	if err != nil {
+		return nil, fmt.Errorf("creating service: %w", err)
+	}
+
+	if config.BaseURL != "" {
+		s.BasePath = config.BaseURL
+	}
+
+	if config.UserAgent != "" {
+		s.UserAgent = config.UserAgent
+	}
+
+	l, err := library.New(config.Client)
+	if err != nil {
+		return nil, fmt.Errorf("creating service: %w", err)
+	}
+	l.BasePath = s.BasePath
+	l.UserAgent = s.UserAgent
+
+	service := &Service{
+		photos: photosLibraryClient{
+			albums:       s.Albums,
+			sharedAlbums: s.SharedAlbums,
+			library:      l,
+		},
+	}
+
+	return service, nil
+}
+
+// Share marks an album as shared and accessible to other users.
+// This action can only be performed on albums which were created by this app.
+//
+// Returns [ErrAlbumNotFound] if the album does not exist.
+func (s *Service) Share(ctx context.Context, albumID string, options SharedAlbumOptions) (*ShareInfo, error) {
+	req := &photoslibrary.ShareAlbumRequest{
+		SharedAlbumOptions: &photoslibrary.SharedAlbumOptions{
+			IsCollaborative: options.IsCollaborative,
+			IsCommentable:   options.IsCommentable,
+		},
+	}
+	res, err := s.photos.Share(albumID, req).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("sharing album: %w", translateGoogleAPIError(err))
+	}
+	if res.ShareInfo == nil {
+		return nil, errors.New("share response has no share info")
+	}
+	return toShareInfo(res.ShareInfo), nil
+}
+
+// Unshare marks a previously shared album as private.
+// All the non-owners will lose access to the album.
+// This action can only be performed on albums which were created by this app.
+//
+// Returns [ErrAlbumNotFound] if the album does not exist.
+func (s *Service) Unshare(ctx context.Context, albumID string) error {
+	if err := s.photos.Unshare(albumID).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("unsharing album: %w", translateGoogleAPIError(err))
+	}
+	return nil
+}
+
+// Join joins a shared album on behalf of the user.
+//
+// Returns [ErrAlbumNotFound] if there is no shared album for the share token.
+func (s *Service) Join(ctx context.Context, shareToken string) error {
+	req := &photoslibrary.JoinSharedAlbumRequest{
+		ShareToken: shareToken,
+	}
+	if _, err := s.photos.Join(req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("joining shared album: %w", translateGoogleAPIError(err))
+	}
+	return nil
+}
+
+// Leave leaves a previously joined shared album on behalf of the user.
+// The user must not own the album.
+//
+// Returns [ErrAlbumNotFound] if there is no shared album for the share token.
+func (s *Service) Leave(ctx context.Context, shareToken string) error {
+	req := &library.LeaveSharedAlbumRequest{
+		ShareToken: shareToken,
+	}
+	if err := s.photos.Leave(req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("leaving shared album: %w", translateGoogleAPIError(err))
+	}
+	return nil
+}
+
+// GetByShareToken returns the shared album specified by the given share token.
+//
+// Returns [ErrAlbumNotFound] if there is no shared album for the share token.
+func (s *Service) GetByShareToken(ctx context.Context, shareToken string) (*Album, error) {
+	res, err := s.photos.Get(shareToken).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("getting shared album by share token: %w", translateGoogleAPIError(err))
+	}
+	album := toAlbum(res)
+	return &album, nil
+}
+
+// maxAlbumsPerPage is the maximum number of shared albums per page.
+// Fewer albums might be returned than the specified number.
+//
+// See https://developers.google.com/photos/library/guides/list#pagination.
+const maxAlbumsPerPage int64 = 50
+
+// List lists all the shared albums available in the Sharing tab of the user's Google Photos app.
+func (s *Service) List(ctx context.Context) ([]Album, error) {
+	var result []Album
+	err := s.photos.List().PageSize(maxAlbumsPerPage).Pages(ctx, func(response *photoslibrary.ListSharedAlbumsResponse) error {
+		for _, res := range response.SharedAlbums {
+			result = append(result, toAlbum(res))
+		}
+		return nil
+	})
+	if err != nil {
+		var emptyResult []Album
+		return emptyResult, fmt.Errorf("listing shared albums: %w", err)
+	}
+	return result, nil
+}
+
+// PaginatedListOptions set the options for the PaginatedList call.
+type PaginatedListOptions struct {
+	Limit     int64
+	PageToken string
+}
+
+// PaginatedList retrieves a specific page of shared albums, allowing for efficient retrieval of albums in pages.
+// Each page contains the predetermined number of albums.
+func (s *Service) PaginatedList(ctx context.Context, options *PaginatedListOptions) (albums []Album, nextPageToken string, err error) {
+	var pageToken string
+	var limit int64
+
+	if options != nil {
+		limit = options.Limit
+		pageToken = options.PageToken
+	}
+
+	if limit == 0 {
+		limit = maxAlbumsPerPage
+	}
+
+	response, err := s.photos.List().PageSize(limit).PageToken(pageToken).Context(ctx).Do()
+	if err != nil {
+		var emptyResult []Album
+		return emptyResult, "", fmt.Errorf("listing shared albums by page: %w", err)
+	}
+
+	return toAlbums(response.SharedAlbums), response.NextPageToken, nil
+}
+
+func translateGoogleAPIError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == http.StatusNotFound {
+			return ErrAlbumNotFound
+		}
+	}
+
+	return err
+}
+
+func toShareInfo(si *photoslibrary.ShareInfo) *ShareInfo {
+	if si == nil {
+		return nil
+	}
+	shareInfo := &ShareInfo{
+		ShareableURL: si.ShareableUrl,
+		ShareToken:   si.ShareToken,
+	}
+	if si.SharedAlbumOptions != nil {
+		shareInfo.SharedAlbumOptions = SharedAlbumOptions{
+			IsCollaborative: si.SharedAlbumOptions.IsCollaborative,
+			IsCommentable:   si.SharedAlbumOptions.IsCommentable,
+		}
+	}
+	return shareInfo
+}
+
+func toAlbum(pa *photoslibrary.Album) Album {
+	return Album{
+		ID:                pa.Id,
+		Title:             pa.Title,
+		ProductURL:        pa.ProductUrl,
+		IsWriteable:       pa.IsWriteable,
+		TotalMediaItems:   pa.TotalMediaItems,
+		CoverPhotoBaseURL: pa.CoverPhotoBaseUrl,
+		ShareInfo:         toShareInfo(pa.ShareInfo),
+	}
+}
+
+func toAlbums(pa []*photoslibrary.Album) []Album {
+	var albums []Album
+	for _, album := range pa {
+		albums = append(albums, toAlbum(album))
+	}
+	return albums
+}
@@ -0,0 +1,222 @@
+package shared_albums_test
+
+import (
+	"context"
+	"errors"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/shared_albums"
+	"net/http"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Should fail without httpClient", func(t *testing.T) {
+		cfg := shared_albums.Config{}
+		_, err := shared_albums.New(cfg)
+		if err == nil {
+			t.Errorf("error was expected but not produced")
+		}
+	})
+
+	t.Run("Should success with an httpClient", func(t *testing.T) {
+		cfg := shared_albums.Config{
+			Client: http.DefaultClient,
+		}
+		_, err := shared_albums.New(cfg)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+	})
+}
+
+func TestSharedAlbumsService_Share(t *testing.T) {
+	testCases := []struct {
+		name          string
+		album         string
+		options       shared_albums.SharedAlbumOptions
+		expectedError error
+		isErrExpected bool
+	}{
+		{"Should share the album", mocks.ExistingAlbum.Id, shared_albums.SharedAlbumOptions{IsCollaborative: true}, nil, false},
+		{"Should return ErrAlbumNotFound if album does not exist", "non-existent", shared_albums.SharedAlbumOptions{}, shared_albums.ErrAlbumNotFound, true},
+		{"Should return error if API fails", mocks.ShouldFailAlbum.Id, shared_albums.SharedAlbumOptions{}, nil, true},
+		{"Should return error if the response has no share info", mocks.AlbumShouldReturnNoShareInfo, shared_albums.SharedAlbumOptions{}, nil, true},
+	}
+
+	s, srv := newSharedAlbumsService(t)
+	defer srv.Close()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.Share(context.Background(), tc.album, tc.options)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("not expected error, want: %v, got: %v", tc.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if got.ShareToken == "" || got.ShareableURL == "" {
+				t.Errorf("want: share token and shareable URL, got: %+v", got)
+			}
+			if tc.options != got.SharedAlbumOptions {
+				t.Errorf("want: %+v, got: %+v", tc.options, got.SharedAlbumOptions)
+			}
+		})
+	}
+}
+
+func TestSharedAlbumsService_Unshare(t *testing.T) {
+	s, srv := newSharedAlbumsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	shareInfo, err := s.Share(ctx, mocks.ExistingAlbum.Id, shared_albums.SharedAlbumOptions{})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	if err := s.Unshare(ctx, mocks.ExistingAlbum.Id); err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+
+	if _, err := s.GetByShareToken(ctx, shareInfo.ShareToken); !errors.Is(err, shared_albums.ErrAlbumNotFound) {
+		t.Errorf("want: %v, got: %v", shared_albums.ErrAlbumNotFound, err)
+	}
+
+	if err := s.Unshare(ctx, "non-existent"); !errors.Is(err, shared_albums.ErrAlbumNotFound) {
+		t.Errorf("want: %v, got: %v", shared_albums.ErrAlbumNotFound, err)
+	}
+}
+
+func TestSharedAlbumsService_JoinAndLeave(t *testing.T) {
+	s, srv := newSharedAlbumsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	if err := s.Join(ctx, mocks.ExistingShareToken); err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	assertSharedAlbums(t, s, 1)
+
+	if err := s.Leave(ctx, mocks.ExistingShareToken); err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	assertSharedAlbums(t, s, 0)
+
+	if err := s.Join(ctx, "non-existent"); !errors.Is(err, shared_albums.ErrAlbumNotFound) {
+		t.Errorf("want: %v, got: %v", shared_albums.ErrAlbumNotFound, err)
+	}
+
+	if err := s.Leave(ctx, "non-existent"); !errors.Is(err, shared_albums.ErrAlbumNotFound) {
+		t.Errorf("want: %v, got: %v", shared_albums.ErrAlbumNotFound, err)
+	}
+
+	shareInfo, err := s.Share(ctx, mocks.ExistingAlbum.Id, shared_albums.SharedAlbumOptions{})
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	if err := s.Leave(ctx, shareInfo.ShareToken); err == nil {
+		t.Errorf("error was expected when leaving an owned album, but not produced")
+	}
+}
+
+func TestSharedAlbumsService_GetByShareToken(t *testing.T) {
+	testCases := []struct {
+		name          string
+		shareToken    string
+		want          string
+		expectedError error
+		isErrExpected bool
+	}{
+		{"Should return the shared album", mocks.ExistingShareToken, "fooId-1", nil, false},
+		{"Should return ErrAlbumNotFound if share token does not exist", "non-existent", "", shared_albums.ErrAlbumNotFound, true},
+		{"Should return error if API fails", mocks.ShareTokenShouldFail, "", nil, true},
+	}
+
+	s, srv := newSharedAlbumsService(t)
+	defer srv.Close()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.GetByShareToken(context.Background(), tc.shareToken)
+			assertExpectedError(tc.isErrExpected, err, t)
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("not expected error, want: %v, got: %v", tc.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if tc.want != got.ID {
+				t.Errorf("want: %s, got: %s", tc.want, got.ID)
+			}
+			if got.ShareInfo == nil || tc.shareToken != got.ShareInfo.ShareToken {
+				t.Errorf("want: share token %s, got: %+v", tc.shareToken, got.ShareInfo)
+			}
+		})
+	}
+}
+
+func TestSharedAlbumsService_PaginatedList(t *testing.T) {
+	s, srv := newSharedAlbumsService(t)
+	defer srv.Close()
+
+	ctx := context.Background()
+	for _, id := range []string{"fooId-2", "fooId-3", "fooId-4"} {
+		if _, err := s.Share(ctx, id, shared_albums.SharedAlbumOptions{}); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+	}
+
+	res, pageToken, err := s.PaginatedList(ctx, &shared_albums.PaginatedListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if len(res) != 2 {
+		t.Errorf("want: %d, got: %d", 2, len(res))
+	}
+	if pageToken == "" {
+		t.Errorf("want: next page token, got: empty")
+	}
+
+	_, _, err = s.PaginatedList(ctx, &shared_albums.PaginatedListOptions{PageToken: mocks.PageTokenShouldFail})
+	if err == nil {
+		t.Errorf("error was expected, but not produced")
+	}
+}
+
+func newSharedAlbumsService(t *testing.T) (*shared_albums.Service, *mocks.MockedGooglePhotosService) {
+	t.Helper()
+	srv := mocks.NewMockedGooglePhotosService()
+	c := shared_albums.Config{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL(),
+	}
+	s, err := shared_albums.New(c)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	return s, srv
+}
+
+func assertSharedAlbums(t *testing.T, s *shared_albums.Service, want int) {
+	t.Helper()
+	got, err := s.List(context.Background())
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if want != len(got) {
+		t.Errorf("want: %d, got: %d", want, len(got))
+	}
+}
+
+func assertExpectedError(isErrExpected bool, err error, t *testing.T) {
+	if isErrExpected && err == nil {
+		t.Fatalf("error was expected, but not produced")
+	}
+	if !isErrExpected && err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+}
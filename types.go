@@ -4,6 +4,10 @@ import (
 	"context"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/shared_albums"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+	"io"
+	"iter"
 )
 
 // OAuth2 scopes used by this API.
@@ -22,16 +26,23 @@ const (
 
 	// PhotoslibraryReadonlyAppcreateddataScope allows managing photos added by this app
 	PhotoslibraryReadonlyAppcreateddataScope = "https://www.googleapis.com/auth/photoslibrary.readonly.appcreateddata"
+
+	// PhotoslibrarySharingScope allows managing and adding to shared albums on your behalf
+	PhotoslibrarySharingScope = "https://www.googleapis.com/auth/photoslibrary.sharing"
 )
 
 // AlbumsService represents a Google Photos client for albums management.
 type AlbumsService interface {
+	AddEnrichment(ctx context.Context, albumId string, enrichment albums.Enrichment, position albums.AlbumPosition) (enrichmentItemId string, err error)
 	AddMediaItems(ctx context.Context, albumId string, mediaItemIds []string) error
+	All(ctx context.Context) iter.Seq2[albums.Album, error]
 	Create(ctx context.Context, title string) (*albums.Album, error)
 	GetById(ctx context.Context, id string) (*albums.Album, error)
 	GetByTitle(ctx context.Context, title string) (*albums.Album, error)
 	List(ctx context.Context) ([]albums.Album, error)
 	PaginatedList(ctx context.Context, options *albums.PaginatedListOptions) (albums []albums.Album, nextPageToken string, err error)
+	Patch(ctx context.Context, albumId string, options albums.PatchOptions) (*albums.Album, error)
+	RemoveMediaItems(ctx context.Context, albumId string, mediaItemIds []string) error
 }
 
 // MediaItemsService represents a Google Photos client for media management.
@@ -40,12 +51,43 @@ type MediaItemsService interface {
 	CreateMany(ctx context.Context, mediaItems []media_items.SimpleMediaItem) ([]*media_items.MediaItem, error)
 	CreateToAlbum(ctx context.Context, albumId string, mediaItem media_items.SimpleMediaItem) (*media_items.MediaItem, error)
 	CreateManyToAlbum(ctx context.Context, albumId string, mediaItems []media_items.SimpleMediaItem) ([]*media_items.MediaItem, error)
+	CreateWithOptions(ctx context.Context, mediaItem media_items.SimpleMediaItem, options media_items.CreateOptions) (*media_items.MediaItem, error)
+	CreateManyWithOptions(ctx context.Context, mediaItems []media_items.SimpleMediaItem, options media_items.CreateOptions) ([]*media_items.MediaItem, error)
 	Get(ctx context.Context, mediaItemId string) (*media_items.MediaItem, error)
+	BatchGet(ctx context.Context, mediaItemIds []string) ([]*media_items.MediaItem, error)
+	Patch(ctx context.Context, mediaItemId string, options media_items.PatchOptions) (*media_items.MediaItem, error)
+	UpdateDescription(ctx context.Context, mediaItemId string, description string) (*media_items.MediaItem, error)
 	ListByAlbum(ctx context.Context, albumId string) ([]*media_items.MediaItem, error)
 	PaginatedList(ctx context.Context, options *media_items.PaginatedListOptions) (mediaItems []media_items.MediaItem, nextPageToken string, err error)
+	Search(ctx context.Context, options media_items.SearchOptions) (mediaItems []media_items.MediaItem, nextPageToken string, err error)
+	SearchAll(ctx context.Context, options media_items.SearchOptions) ([]media_items.MediaItem, error)
+	All(ctx context.Context) iter.Seq2[media_items.MediaItem, error]
+	ByAlbum(ctx context.Context, albumId string) iter.Seq2[media_items.MediaItem, error]
+	SearchSeq(ctx context.Context, options media_items.SearchOptions) iter.Seq2[media_items.MediaItem, error]
+	Download(ctx context.Context, mediaItem media_items.MediaItem, options media_items.DownloadOptions) (io.ReadCloser, error)
+}
+
+// SharedAlbumsService represents a Google Photos client for shared albums management.
+type SharedAlbumsService interface {
+	Share(ctx context.Context, albumId string, options shared_albums.SharedAlbumOptions) (*shared_albums.ShareInfo, error)
+	Unshare(ctx context.Context, albumId string) error
+	Join(ctx context.Context, shareToken string) error
+	Leave(ctx context.Context, shareToken string) error
+	GetByShareToken(ctx context.Context, shareToken string) (*shared_albums.Album, error)
+	List(ctx context.Context) ([]shared_albums.Album, error)
+	PaginatedList(ctx context.Context, options *shared_albums.PaginatedListOptions) (albums []shared_albums.Album, nextPageToken string, err error)
 }
 
 // MediaUploader represents a Google Photos client fo media upload.
 type MediaUploader interface {
 	UploadFile(ctx context.Context, filePath string) (uploadToken string, err error)
+	UploadReader(ctx context.Context, r io.Reader, size int64, name string) (uploadToken string, err error)
+}
+
+// MediaUploaderWithOptions represents a MediaUploader accepting the options of every upload.
+// It's required to report the progress of the uploads, see UploadOptions.
+type MediaUploaderWithOptions interface {
+	MediaUploader
+	UploadFileWithOptions(ctx context.Context, filePath string, options uploader.UploadOptions) (uploadToken string, err error)
+	UploadReaderWithOptions(ctx context.Context, r io.Reader, size int64, name string, options uploader.UploadOptions) (uploadToken string, err error)
 }
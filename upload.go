@@ -2,32 +2,291 @@ package gphotos
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+	"hash"
+	"io"
+	"path/filepath"
 )
 
+// UploadOptions set the options for the UploadWithOptions and UploadFromReaderWithOptions calls.
+type UploadOptions struct {
+	// AlbumID is the album where the media item is added. If empty, the media item
+	// is only added to the library.
+	AlbumID string
+
+	// Position in the album where the media item is added. It requires AlbumID.
+	// The zero value adds the media item to the end of the album.
+	Position albums.AlbumPosition
+
+	// OnProgress, if set, is called with the progress of the upload. It requires an
+	// Uploader implementing MediaUploaderWithOptions, like the ones in the uploader package.
+	OnProgress uploader.ProgressFunc
+}
+
 // Upload uploads the specified file and creates the media item
-// in Google Photos.
+// in Google Photos. The media item is named after the file's base name.
+//
+// If the client has an UploadIndex, files with the same content as an already
+// uploaded one are not uploaded again, and the existing media item is returned.
+// An *UploadIndexError is returned if the media item can't be recorded.
+//
+// Use UploadWithOptions to get the progress of the upload.
 func (c *Client) Upload(ctx context.Context, filePath string) (*media_items.MediaItem, error) {
-	token, err := c.Uploader.UploadFile(ctx, filePath)
+	return c.UploadWithOptions(ctx, filePath, UploadOptions{})
+}
+
+// UploadToAlbum uploads the specified file and creates the media item
+// in the specified album in Google Photos. The media item is named after
+// the file's base name.
+//
+// If the client has an UploadIndex, files with the same content as an already
+// uploaded one are not uploaded again, and the existing media item is added to the album.
+// An *UploadIndexError is returned if the media item can't be recorded.
+func (c *Client) UploadToAlbum(ctx context.Context, albumId string, filePath string) (*media_items.MediaItem, error) {
+	return c.UploadWithOptions(ctx, filePath, UploadOptions{AlbumID: albumId})
+}
+
+// UploadToAlbumAtPosition uploads the specified file and creates the media item
+// in the specified album in Google Photos, at the specified position.
+// See UploadWithOptions.
+func (c *Client) UploadToAlbumAtPosition(ctx context.Context, albumId string, filePath string, position albums.AlbumPosition) (*media_items.MediaItem, error) {
+	return c.UploadWithOptions(ctx, filePath, UploadOptions{AlbumID: albumId, Position: position})
+}
+
+// UploadWithOptions uploads the specified file and creates the media item
+// in Google Photos, with the given options. The media item is named after
+// the file's base name. The options are validated before uploading the file.
+//
+// If the client has an UploadIndex, files with the same content as an already
+// uploaded one are not uploaded again, and the existing media item is added to the
+// end of the album, if any: the API can't add existing media items at a position.
+// An *UploadIndexError is returned if the media item can't be recorded.
+func (c *Client) UploadWithOptions(ctx context.Context, filePath string, options UploadOptions) (*media_items.MediaItem, error) {
+	createOptions, err := options.createOptions()
+	if err != nil {
+		return nil, err
+	}
+	hash, mediaItem, err := c.findUploaded(ctx, filePath)
 	if err != nil {
 		return nil, err
 	}
-	return c.MediaItems.Create(ctx, media_items.SimpleMediaItem{
+	if mediaItem != nil {
+		return c.addUploaded(ctx, options.AlbumID, mediaItem)
+	}
+	token, err := c.uploadFile(ctx, filePath, options)
+	if err != nil {
+		return nil, err
+	}
+	mediaItem, err = c.MediaItems.CreateWithOptions(ctx, media_items.SimpleMediaItem{
 		UploadToken: token,
-		Filename:    filePath,
-	})
+		Filename:    filepath.Base(filePath),
+	}, createOptions)
+	if err != nil {
+		return nil, err
+	}
+	return c.recordUploaded(hash, mediaItem)
 }
 
-// UploadToAlbum uploads the specified file and creates the media item
-// in the specified album in Google Photos.
-func (c *Client) UploadToAlbum(ctx context.Context, albumId string, filePath string) (*media_items.MediaItem, error) {
-	token, err := c.Uploader.UploadFile(ctx, filePath)
+// UploadFromReader uploads the content of r, of the given size, and creates
+// the media item in Google Photos. The media item is named after name.
+//
+// If the client has an UploadIndex, the created media item is recorded. Readers
+// implementing io.ReadSeeker with the same content as an already uploaded file are
+// not uploaded again, and the existing media item is returned. The rest of them can
+// only be read once, so they're always uploaded.
+// An *UploadIndexError is returned if the media item can't be recorded.
+func (c *Client) UploadFromReader(ctx context.Context, r io.Reader, size int64, name string) (*media_items.MediaItem, error) {
+	return c.UploadFromReaderWithOptions(ctx, r, size, name, UploadOptions{})
+}
+
+// UploadFromReaderToAlbum uploads the content of r, of the given size, and creates
+// the media item in the specified album in Google Photos. The media item is named after name.
+//
+// The UploadIndex is used as in UploadFromReader. Existing media items are added to the album.
+func (c *Client) UploadFromReaderToAlbum(ctx context.Context, albumId string, r io.Reader, size int64, name string) (*media_items.MediaItem, error) {
+	return c.UploadFromReaderWithOptions(ctx, r, size, name, UploadOptions{AlbumID: albumId})
+}
+
+// UploadFromReaderWithOptions uploads the content of r, of the given size, and creates
+// the media item in Google Photos, with the given options. The media item is named after name.
+//
+// The UploadIndex is used as in UploadFromReader. Existing media items are added to the
+// end of the album, if any, as in UploadWithOptions.
+func (c *Client) UploadFromReaderWithOptions(ctx context.Context, r io.Reader, size int64, name string, options UploadOptions) (*media_items.MediaItem, error) {
+	createOptions, err := options.createOptions()
+	if err != nil {
+		return nil, err
+	}
+	var hash string
+	var streamHash *contentHash
+	if c.UploadIndex != nil {
+		if rs, ok := r.(io.ReadSeeker); ok {
+			var mediaItem *media_items.MediaItem
+			hash, mediaItem, err = c.findUploadedReader(ctx, rs, size, name)
+			if err != nil {
+				return nil, err
+			}
+			if mediaItem != nil {
+				return c.addUploaded(ctx, options.AlbumID, mediaItem)
+			}
+		} else {
+			// The content is hashed while it's uploaded.
+			streamHash = newContentHash()
+			r = io.TeeReader(r, streamHash)
+		}
+	}
+	token, err := c.uploadReader(ctx, r, size, name, options)
 	if err != nil {
 		return nil, err
 	}
 	item := media_items.SimpleMediaItem{
 		UploadToken: token,
-		Filename:    filePath,
+		Filename:    name,
+	}
+	mediaItem, err := c.MediaItems.CreateWithOptions(ctx, item, createOptions)
+	if err != nil {
+		return nil, err
+	}
+	// Streams are hashed only if the whole content has been read exactly once.
+	if streamHash != nil && streamHash.n == size {
+		hash = streamHash.sum()
+	}
+	return c.recordUploaded(hash, mediaItem)
+}
+
+// createOptions returns the validated options to create the uploaded media item.
+func (o UploadOptions) createOptions() (media_items.CreateOptions, error) {
+	options := media_items.CreateOptions{
+		AlbumID:  o.AlbumID,
+		Position: o.Position,
+	}
+	if err := options.Validate(); err != nil {
+		return media_items.CreateOptions{}, err
+	}
+	return options, nil
+}
+
+// uploadFile uploads the specified file with the Uploader, reporting its progress to options.OnProgress.
+func (c *Client) uploadFile(ctx context.Context, filePath string, options UploadOptions) (string, error) {
+	if options.OnProgress == nil {
+		return c.Uploader.UploadFile(ctx, filePath)
 	}
-	return c.MediaItems.CreateToAlbum(ctx, albumId, item)
+	u, ok := c.Uploader.(MediaUploaderWithOptions)
+	if !ok {
+		return "", fmt.Errorf("uploading file %s: uploader %T can't report progress", filePath, c.Uploader)
+	}
+	return u.UploadFileWithOptions(ctx, filePath, uploader.UploadOptions{OnProgress: options.OnProgress})
+}
+
+// uploadReader is like uploadFile, for the content of r.
+func (c *Client) uploadReader(ctx context.Context, r io.Reader, size int64, name string, options UploadOptions) (string, error) {
+	if options.OnProgress == nil {
+		return c.Uploader.UploadReader(ctx, r, size, name)
+	}
+	u, ok := c.Uploader.(MediaUploaderWithOptions)
+	if !ok {
+		return "", fmt.Errorf("uploading %s: uploader %T can't report progress", name, c.Uploader)
+	}
+	return u.UploadReaderWithOptions(ctx, r, size, name, uploader.UploadOptions{OnProgress: options.OnProgress})
+}
+
+// findUploaded returns the content hash of the specified file, and the media item
+// already created from the same content, if any. The hash is empty without UploadIndex.
+func (c *Client) findUploaded(ctx context.Context, filePath string) (hash string, mediaItem *media_items.MediaItem, err error) {
+	if c.UploadIndex == nil {
+		return "", nil, nil
+	}
+	hash, err = uploader.HashFile(filePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("uploading file %s: %w", filePath, err)
+	}
+	mediaItem, err = c.findIndexed(ctx, hash)
+	if err != nil {
+		return "", nil, err
+	}
+	return hash, mediaItem, nil
+}
+
+// findUploadedReader is like findUploaded, for the size bytes of rs. It hashes the
+// content from the beginning, as uploaders read it.
+func (c *Client) findUploadedReader(ctx context.Context, rs io.ReadSeeker, size int64, name string) (hash string, mediaItem *media_items.MediaItem, err error) {
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return "", nil, fmt.Errorf("uploading %s: %w", name, err)
+	}
+	h := newContentHash()
+	if _, err := io.Copy(h, io.LimitReader(rs, size)); err != nil {
+		return "", nil, fmt.Errorf("uploading %s: hashing content: %w", name, err)
+	}
+	hash = h.sum()
+	mediaItem, err = c.findIndexed(ctx, hash)
+	if err != nil {
+		return "", nil, err
+	}
+	return hash, mediaItem, nil
+}
+
+// findIndexed returns the media item created from the content with the given hash, if any.
+// Media items that don't exist anymore are removed from the index.
+func (c *Client) findIndexed(ctx context.Context, hash string) (*media_items.MediaItem, error) {
+	mediaItemId, found := c.UploadIndex.Get(hash)
+	if !found {
+		return nil, nil
+	}
+	mediaItem, err := c.MediaItems.Get(ctx, mediaItemId)
+	if errors.Is(err, media_items.ErrMediaItemNotFound) {
+		// The media item has been deleted, so the content is uploaded again.
+		return nil, c.UploadIndex.Delete(hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mediaItem, nil
+}
+
+// addUploaded adds an already uploaded media item to the album, if any.
+func (c *Client) addUploaded(ctx context.Context, albumId string, mediaItem *media_items.MediaItem) (*media_items.MediaItem, error) {
+	if albumId == "" {
+		return mediaItem, nil
+	}
+	if err := c.Albums.AddMediaItems(ctx, albumId, []string{mediaItem.ID}); err != nil {
+		return nil, err
+	}
+	return mediaItem, nil
+}
+
+// recordUploaded records the media item created from the content with the given hash.
+// An *UploadIndexError, keeping the created media item, is returned if it can't be recorded.
+func (c *Client) recordUploaded(hash string, mediaItem *media_items.MediaItem) (*media_items.MediaItem, error) {
+	if c.UploadIndex == nil || hash == "" {
+		return mediaItem, nil
+	}
+	if err := c.UploadIndex.Set(hash, mediaItem.ID); err != nil {
+		return nil, &UploadIndexError{MediaItem: mediaItem, Err: err}
+	}
+	return mediaItem, nil
+}
+
+// contentHash computes the content hash of what is written to it, with uploader.NewContentHash,
+// so files and readers with the same content match. It counts the written bytes.
+type contentHash struct {
+	h hash.Hash
+	n int64
+}
+
+func newContentHash() *contentHash {
+	return &contentHash{h: uploader.NewContentHash()}
+}
+
+func (c *contentHash) Write(p []byte) (int, error) {
+	n, err := c.h.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *contentHash) sum() string {
+	return uploader.ContentHashSum(c.h)
 }
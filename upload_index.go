@@ -0,0 +1,120 @@
+package gphotos
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/jsonl"
+)
+
+// UploadIndex maps the content hash of uploaded files, as computed by uploader.HashFile,
+// with the ID of the media item created from them. See Client.UploadIndex.
+//
+// Implementations used by Client.UploadMany must be safe for concurrent use.
+type UploadIndex interface {
+	Get(hash string) (mediaItemId string, found bool)
+	Set(hash string, mediaItemId string) error
+	Delete(hash string) error
+}
+
+// MemoryUploadIndex is an UploadIndex kept in memory. It's safe for concurrent use.
+type MemoryUploadIndex struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// NewMemoryUploadIndex returns an empty MemoryUploadIndex.
+func NewMemoryUploadIndex() *MemoryUploadIndex {
+	return &MemoryUploadIndex{entries: make(map[string]string)}
+}
+
+// Get implements UploadIndex.
+func (i *MemoryUploadIndex) Get(hash string) (string, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	id, ok := i.entries[hash]
+	return id, ok
+}
+
+// Set implements UploadIndex.
+func (i *MemoryUploadIndex) Set(hash string, mediaItemId string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.entries[hash] = mediaItemId
+	return nil
+}
+
+// Delete implements UploadIndex.
+func (i *MemoryUploadIndex) Delete(hash string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.entries, hash)
+	return nil
+}
+
+// FileUploadIndex is an UploadIndex persisted in a file, so files are not uploaded again
+// in following runs of the program. Every change is appended to the file as a JSON line,
+// and the file is compacted when it's opened. The file can be shared by multiple processes.
+type FileUploadIndex struct {
+	mem *MemoryUploadIndex
+	log *jsonl.Log[indexEntry]
+}
+
+// indexEntry is a line of a FileUploadIndex. An empty MediaItemID deletes the hash.
+type indexEntry struct {
+	Hash        string `json:"hash"`
+	MediaItemID string `json:"mediaItemId,omitempty"`
+}
+
+// OpenFileUploadIndex opens, or creates, the index stored in the given file.
+// The caller must close it.
+func OpenFileUploadIndex(name string) (*FileUploadIndex, error) {
+	mem := NewMemoryUploadIndex()
+	log, err := jsonl.Open(name, func(e indexEntry) {
+		if e.MediaItemID == "" {
+			delete(mem.entries, e.Hash)
+			return
+		}
+		mem.entries[e.Hash] = e.MediaItemID
+	}, func() []indexEntry {
+		entries := make([]indexEntry, 0, len(mem.entries))
+		for hash, id := range mem.entries {
+			entries = append(entries, indexEntry{Hash: hash, MediaItemID: id})
+		}
+		return entries
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening upload index: %w", err)
+	}
+	return &FileUploadIndex{mem: mem, log: log}, nil
+}
+
+// Get implements UploadIndex.
+func (i *FileUploadIndex) Get(hash string) (string, bool) {
+	return i.mem.Get(hash)
+}
+
+// Set implements UploadIndex.
+func (i *FileUploadIndex) Set(hash string, mediaItemId string) error {
+	if mediaItemId == "" {
+		return errors.New("media item id is empty")
+	}
+	if err := i.log.Append(indexEntry{Hash: hash, MediaItemID: mediaItemId}); err != nil {
+		return fmt.Errorf("writing upload index: %w", err)
+	}
+	return i.mem.Set(hash, mediaItemId)
+}
+
+// Delete implements UploadIndex.
+func (i *FileUploadIndex) Delete(hash string) error {
+	if err := i.log.Append(indexEntry{Hash: hash}); err != nil {
+		return fmt.Errorf("writing upload index: %w", err)
+	}
+	return i.mem.Delete(hash)
+}
+
+// Close closes the index file.
+func (i *FileUploadIndex) Close() error {
+	return i.log.Close()
+}
@@ -0,0 +1,106 @@
+package gphotos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+)
+
+func TestMemoryUploadIndex(t *testing.T) {
+	testUploadIndex(t, gphotos.NewMemoryUploadIndex())
+}
+
+func TestFileUploadIndex(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "index.jsonl")
+	index, err := gphotos.OpenFileUploadIndex(name)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	testUploadIndex(t, index)
+
+	t.Run("Should keep media items after closing", func(t *testing.T) {
+		if err := index.Set("fooHash", "fooId"); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if err := index.Set("barHash", "barId"); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if err := index.Delete("barHash"); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if err := index.Close(); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+
+		// A write interrupted by a crash.
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if _, err := f.WriteString(`{"hash":"bazHa`); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		_ = f.Close()
+
+		index, err := gphotos.OpenFileUploadIndex(name)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		defer index.Close()
+		assertIndexedMediaItem(t, index, "fooHash", "fooId", true)
+		assertIndexedMediaItem(t, index, "barHash", "", false)
+
+		if err := index.Set("bazHash", "bazId"); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		reopened, err := gphotos.OpenFileUploadIndex(name)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		defer reopened.Close()
+		assertIndexedMediaItem(t, reopened, "bazHash", "bazId", true)
+	})
+
+	t.Run("Should fail when writing a closed index", func(t *testing.T) {
+		if err := index.Set("fooHash", "fooId"); err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+}
+
+func testUploadIndex(t *testing.T, index gphotos.UploadIndex) {
+	t.Run("Should return not found for unknown hashes", func(t *testing.T) {
+		assertIndexedMediaItem(t, index, "unknownHash", "", false)
+	})
+
+	t.Run("Should return the media item of a hash", func(t *testing.T) {
+		if err := index.Set("fooHash", "fooId"); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		assertIndexedMediaItem(t, index, "fooHash", "fooId", true)
+	})
+
+	t.Run("Should replace the media item of a hash", func(t *testing.T) {
+		if err := index.Set("fooHash", "barId"); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		assertIndexedMediaItem(t, index, "fooHash", "barId", true)
+	})
+
+	t.Run("Should delete a hash", func(t *testing.T) {
+		if err := index.Delete("fooHash"); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		assertIndexedMediaItem(t, index, "fooHash", "", false)
+	})
+}
+
+func assertIndexedMediaItem(t *testing.T, index gphotos.UploadIndex, hash, want string, wantFound bool) {
+	t.Helper()
+	got, found := index.Get(hash)
+	if wantFound != found || want != got {
+		t.Errorf("want: %q (found: %t), got: %q (found: %t)", want, wantFound, got, found)
+	}
+}
@@ -0,0 +1,219 @@
+package gphotos
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+)
+
+// defaultUploadConcurrency is the number of concurrent uploads when none is configured.
+const defaultUploadConcurrency = 4
+
+// uploadBatchDelay is the longest time an uploaded file waits for more files to be created in the same batch.
+const uploadBatchDelay = time.Second
+
+// UploadManyOptions set the options for the UploadMany call.
+type UploadManyOptions struct {
+	// AlbumID is the album where the media items are created. If empty,
+	// media items are only added to the library.
+	AlbumID string
+
+	// Concurrency is the maximum number of concurrent uploads. Defaults to 4.
+	Concurrency int
+
+	// OnResult, if set, is called with the result of every file as soon as it's known.
+	// Calls are sequential, from the goroutine calling UploadMany.
+	OnResult func(result UploadResult)
+}
+
+// UploadResult is the result of uploading one file.
+type UploadResult struct {
+	// Path of the uploaded file.
+	Path string
+
+	// MediaItem is the created media item, or nil if Err is set.
+	MediaItem *media_items.MediaItem
+
+	// Err is the reason why the file couldn't be uploaded or created.
+	Err error
+}
+
+// UploadMany uploads the specified files concurrently, and creates the media items in batches
+// of up to 50 items per call. If an album id is specified, media items are also added to the album,
+// in the order their uploads finish. Media items are named after the file's base name.
+//
+// A batch is created when it's full, when no other uploads are pending, or a second after its first
+// file was uploaded, so results are streamed to OnResult while the rest of the files are uploaded.
+//
+// If the client has an UploadIndex, files with the same content as an already uploaded one are not
+// uploaded again, and the existing media item is used, as in UploadToAlbum. The UploadIndex must be
+// safe for concurrent use.
+//
+// Results are returned in the same order as filePaths. A failed file doesn't stop the rest of them.
+// When ctx is done, pending files are not uploaded and their results have the context error,
+// which is also returned if any result has it. UploadMany doesn't return until all its goroutines have finished.
+func (c *Client) UploadMany(ctx context.Context, filePaths []string, options UploadManyOptions) ([]UploadResult, error) {
+	if options.Concurrency < 0 {
+		return nil, errors.New("concurrency is negative")
+	}
+	concurrency := options.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultUploadConcurrency
+	}
+
+	results := make([]UploadResult, len(filePaths))
+	reported := make([]bool, len(filePaths))
+	report := func(index int, mediaItem *media_items.MediaItem, err error) {
+		results[index] = UploadResult{Path: filePaths[index], MediaItem: mediaItem, Err: err}
+		reported[index] = true
+		if options.OnResult != nil {
+			options.OnResult(results[index])
+		}
+	}
+
+	// pending is the number of files sent, or being sent, to the workers, whose upload hasn't been received.
+	var pending atomic.Int64
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range filePaths {
+			pending.Add(1)
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				pending.Add(-1)
+				return
+			}
+		}
+	}()
+
+	uploads := make(chan uploadedFile)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(filePaths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				uploads <- c.uploadManyFile(ctx, options.AlbumID, i, filePaths[i])
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(uploads)
+	}()
+
+	var batch []uploadedFile
+	var delay <-chan time.Time // started by the first file of the batch.
+	create := func() {
+		c.createUploadedFiles(ctx, options.AlbumID, filePaths, batch, report)
+		batch, delay = nil, nil
+	}
+loop:
+	for {
+		select {
+		case u, ok := <-uploads:
+			if !ok {
+				break loop
+			}
+			pending.Add(-1)
+			if u.err != nil || u.mediaItem != nil {
+				report(u.index, u.mediaItem, u.err)
+			} else {
+				batch = append(batch, u)
+				if len(batch) == 1 {
+					delay = time.After(uploadBatchDelay)
+				}
+			}
+			if len(batch) == media_items.MaxMediaItemsPerBatch || (len(batch) > 0 && pending.Load() == 0) {
+				create()
+			}
+		case <-delay:
+			create()
+		}
+	}
+	if len(batch) > 0 {
+		create()
+	}
+
+	// Files not sent to the workers because ctx is done.
+	for i := range filePaths {
+		if !reported[i] {
+			report(i, nil, ctx.Err())
+		}
+	}
+	if err := ctx.Err(); err != nil && slices.ContainsFunc(results, func(r UploadResult) bool { return errors.Is(r.Err, err) }) {
+		return results, err
+	}
+	return results, nil
+}
+
+// uploadedFile is the upload result of the file at index in the UploadMany input.
+// Files already uploaded have the existing media item, instead of an upload token.
+type uploadedFile struct {
+	index       int
+	hash        string
+	uploadToken string
+	mediaItem   *media_items.MediaItem
+	err         error
+}
+
+// uploadManyFile uploads the file at index in the UploadMany input, unless its content
+// has already been uploaded. The existing media item is added to the album, if any.
+func (c *Client) uploadManyFile(ctx context.Context, albumId string, index int, filePath string) uploadedFile {
+	hash, mediaItem, err := c.findUploaded(ctx, filePath)
+	if err != nil {
+		return uploadedFile{index: index, err: err}
+	}
+	if mediaItem != nil {
+		mediaItem, err = c.addUploaded(ctx, albumId, mediaItem)
+		return uploadedFile{index: index, mediaItem: mediaItem, err: err}
+	}
+	token, err := c.Uploader.UploadFile(ctx, filePath)
+	return uploadedFile{index: index, hash: hash, uploadToken: token, err: err}
+}
+
+// createUploadedFiles creates the media items of up to media_items.MaxMediaItemsPerBatch uploaded files,
+// in their input order, reporting the result of every one of them.
+func (c *Client) createUploadedFiles(ctx context.Context, albumId string, filePaths []string, batch []uploadedFile, report func(index int, mediaItem *media_items.MediaItem, err error)) {
+	slices.SortFunc(batch, func(a, b uploadedFile) int { return cmp.Compare(a.index, b.index) })
+
+	mediaItems := make([]media_items.SimpleMediaItem, len(batch))
+	for i, u := range batch {
+		mediaItems[i] = media_items.SimpleMediaItem{
+			UploadToken: u.uploadToken,
+			Filename:    filepath.Base(filePaths[u.index]),
+		}
+	}
+
+	created, err := c.MediaItems.CreateManyToAlbum(ctx, albumId, mediaItems)
+	errs := make([]error, len(batch))
+	if batchErr := (*media_items.BatchCreateError)(nil); errors.As(err, &batchErr) {
+		for _, e := range batchErr.Errors {
+			errs[e.Index] = e
+		}
+	} else if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+	}
+
+	for i, u := range batch {
+		switch {
+		case errs[i] != nil:
+			report(u.index, nil, errs[i])
+		case i >= len(created) || created[i] == nil:
+			report(u.index, nil, errors.New("no media item was created"))
+		default:
+			mediaItem, err := c.recordUploaded(u.hash, created[i])
+			report(u.index, mediaItem, err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package gphotos_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+)
+
+func TestClient_UploadMany(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+	client := newUploadManyClient(t, srv)
+
+	dir := t.TempDir()
+	var paths []string
+	for i := range 120 {
+		name := fmt.Sprintf("file-%d.jpg", i)
+		switch i % 40 {
+		case 7:
+			name = "fail-" + name
+		case 9:
+			name = "empty-" + name
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		paths = append(paths, path)
+	}
+	ctx := context.Background()
+
+	t.Run("Should upload files to an album", func(t *testing.T) {
+		var called int
+		results, err := client.UploadMany(ctx, paths, gphotos.UploadManyOptions{
+			AlbumID:     "fooId-1",
+			Concurrency: 8,
+			OnResult:    func(gphotos.UploadResult) { called++ },
+		})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if len(paths) != called {
+			t.Errorf("want: %d calls, got: %d", len(paths), called)
+		}
+		var created int
+		for i, res := range results {
+			if paths[i] != res.Path {
+				t.Errorf("want: %s, got: %s", paths[i], res.Path)
+			}
+			name := filepath.Base(res.Path)
+			failed := strings.HasPrefix(name, "fail-") || strings.HasPrefix(name, "empty-")
+			if failed != (res.Err != nil) || failed != (res.MediaItem == nil) {
+				t.Errorf("%s: unexpected result, media item: %v, err: %v", res.Path, res.MediaItem, res.Err)
+			}
+			if res.MediaItem != nil {
+				created++
+				if name != res.MediaItem.Filename {
+					t.Errorf("want: %s, got: %s", name, res.MediaItem.Filename)
+				}
+			}
+		}
+		if want := len(paths) - 6; want != created {
+			t.Errorf("want: %d created, got: %d", want, created)
+		}
+		// Albums in the mocked service contain all the fake media items.
+		if want, got := mocks.AvailableMediaItems+created, len(srv.AlbumMediaItems("fooId-1")); want != got {
+			t.Errorf("want: %d media items in the album, got: %d", want, got)
+		}
+	})
+
+	t.Run("Should stop when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		results, err := client.UploadMany(ctx, paths, gphotos.UploadManyOptions{
+			Concurrency: 2,
+			OnResult:    func(gphotos.UploadResult) { cancel() },
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want: %v, got: %v", context.Canceled, err)
+		}
+		var cancelled int
+		for _, res := range results {
+			if errors.Is(res.Err, context.Canceled) {
+				cancelled++
+			}
+		}
+		if cancelled < len(paths)-maxInFlight(2) {
+			t.Errorf("want: at least %d cancelled, got: %d", len(paths)-maxInFlight(2), cancelled)
+		}
+	})
+
+	t.Run("Should report results while other files are uploaded", func(t *testing.T) {
+		blocked, err := gphotos.NewClientWithBaseURL(http.DefaultClient, srv.URL())
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		release := make(chan struct{})
+		blocked.Uploader = blockingUploader{MediaUploader: client.Uploader, path: paths[1], release: release}
+		var once sync.Once
+		results, err := blocked.UploadMany(ctx, paths[:2], gphotos.UploadManyOptions{
+			Concurrency: 2,
+			OnResult:    func(gphotos.UploadResult) { once.Do(func() { close(release) }) },
+		})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		for _, res := range results {
+			if res.Err != nil {
+				t.Errorf("%s: error was not expected, err: %s", res.Path, res.Err)
+			}
+		}
+	})
+
+	t.Run("Should not return the context error if every file has been reported", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var called int
+		_, err := client.UploadMany(ctx, paths[:3], gphotos.UploadManyOptions{
+			Concurrency: 1,
+			OnResult: func(gphotos.UploadResult) {
+				if called++; called == 3 {
+					cancel()
+				}
+			},
+		})
+		if err != nil {
+			t.Errorf("error was not expected, err: %s", err)
+		}
+	})
+
+	t.Run("Should report files without created media item", func(t *testing.T) {
+		short, err := gphotos.NewClientWithBaseURL(http.DefaultClient, srv.URL())
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		short.Uploader = client.Uploader
+		short.MediaItems = truncatingMediaItems{client.MediaItems}
+		results, err := short.UploadMany(ctx, paths[:3], gphotos.UploadManyOptions{})
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if results[0].Err != nil || results[0].MediaItem == nil {
+			t.Errorf("want: a media item, got: %v, err: %v", results[0].MediaItem, results[0].Err)
+		}
+		for _, res := range results[1:] {
+			if res.Err == nil || res.MediaItem != nil {
+				t.Errorf("%s: want: an error, got: %v", res.Path, res.MediaItem)
+			}
+		}
+	})
+
+	t.Run("Should fail with negative concurrency", func(t *testing.T) {
+		if _, err := client.UploadMany(ctx, paths, gphotos.UploadManyOptions{Concurrency: -1}); err == nil {
+			t.Errorf("error was expected but not produced")
+		}
+	})
+}
+
+// maxInFlight is the maximum number of files that can be processed, but not reported, by UploadMany
+// when its context is cancelled: one per worker plus one waiting to be sent to them.
+func maxInFlight(concurrency int) int {
+	return concurrency + 1
+}
+
+// fakeUploader fails to upload files named fail-*, and makes
+// the creation of files named empty-* fail.
+type fakeUploader struct {
+	gphotos.MediaUploader
+}
+
+func (u fakeUploader) UploadFile(ctx context.Context, filePath string) (string, error) {
+	switch name := filepath.Base(filePath); {
+	case strings.HasPrefix(name, "fail-"):
+		return "", errors.New("upload failed")
+	case strings.HasPrefix(name, "empty-"):
+		return mocks.ShouldReturnEmptyMediaItem, nil
+	}
+	return u.MediaUploader.UploadFile(ctx, filePath)
+}
+
+// blockingUploader doesn't upload path until release is closed, or fails after a while.
+type blockingUploader struct {
+	gphotos.MediaUploader
+	path    string
+	release <-chan struct{}
+}
+
+func (u blockingUploader) UploadFile(ctx context.Context, filePath string) (string, error) {
+	if filePath == u.path {
+		select {
+		case <-u.release:
+		case <-time.After(5 * time.Second):
+			return "", errors.New("upload not released: no results reported while uploading")
+		}
+	}
+	return u.MediaUploader.UploadFile(ctx, filePath)
+}
+
+// truncatingMediaItems returns only the first created media item.
+type truncatingMediaItems struct {
+	gphotos.MediaItemsService
+}
+
+func (m truncatingMediaItems) CreateManyToAlbum(ctx context.Context, albumId string, mediaItems []media_items.SimpleMediaItem) ([]*media_items.MediaItem, error) {
+	created, err := m.MediaItemsService.CreateManyToAlbum(ctx, albumId, mediaItems)
+	return created[:min(len(created), 1)], err
+}
+
+func newUploadManyClient(t *testing.T, srv *mocks.MockedGooglePhotosService) *gphotos.Client {
+	t.Helper()
+	client, err := gphotos.NewClientWithBaseURL(http.DefaultClient, srv.URL())
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u, err := uploader.NewSimpleUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+	client.Uploader = fakeUploader{u}
+	return client
+}
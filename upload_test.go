@@ -1,13 +1,18 @@
 package gphotos_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/media_items"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+	"io"
 	"net/http"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -49,6 +54,55 @@ func TestClient_Upload(t *testing.T) {
 		if want != mediaItem.ID {
 			t.Errorf("want: %s, got: %s", want, mediaItem.ID)
 		}
+		if "upload-success" != mediaItem.Filename {
+			t.Errorf("want: %s, got: %s", "upload-success", mediaItem.Filename)
+		}
+	})
+
+	t.Run("Should report the progress of the upload", func(t *testing.T) {
+		srv := mocks.NewMockedGooglePhotosService()
+		defer srv.Close()
+
+		client, err := gphotos.NewClientWithBaseURL(http.DefaultClient, srv.URL())
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		mockedUploader, err := uploader.NewResumableUploader(http.DefaultClient)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		mockedUploader.BaseURL = srv.URL() + "/v1/uploads"
+		client.Uploader = mockedUploader
+
+		var last uploader.Progress
+		options := gphotos.UploadOptions{OnProgress: func(p uploader.Progress) { last = p }}
+		if _, err := client.UploadWithOptions(context.Background(), "testdata/upload-success", options); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if "upload-success" != last.Name || 50408 != last.Sent || 50408 != last.Total {
+			t.Errorf("want: upload-success, 50408 of 50408 bytes sent, got: %+v", last)
+		}
+	})
+
+	t.Run("Should fail to report the progress without an uploader accepting options", func(t *testing.T) {
+		srv := mocks.NewMockedGooglePhotosService()
+		defer srv.Close()
+
+		client, err := gphotos.NewClientWithBaseURL(http.DefaultClient, srv.URL())
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		mockedUploader, err := uploader.NewSimpleUploader(http.DefaultClient)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		mockedUploader.BaseURL = srv.URL() + "/v1/uploads"
+		client.Uploader = &countingUploader{MediaUploader: mockedUploader}
+
+		options := gphotos.UploadOptions{OnProgress: func(uploader.Progress) {}}
+		if _, err := client.UploadWithOptions(context.Background(), "testdata/upload-success", options); err == nil {
+			t.Errorf("error was expected but not produced")
+		}
 	})
 
 	t.Run("Should fail with invalid file", func(t *testing.T) {
@@ -127,3 +181,283 @@ func TestClient_UploadToAlbum(t *testing.T) {
 		}
 	})
 }
+
+func TestClient_Upload_UploadIndex(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	client, err := gphotos.NewClientWithBaseURL(http.DefaultClient, srv.URL())
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u, err := uploader.NewSimpleUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+	counter := &countingUploader{MediaUploader: u}
+	client.Uploader = counter
+
+	hash, err := uploader.HashFile("testdata/upload-success")
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	testCases := []struct {
+		name        string
+		indexed     string // media item ID in the index before uploading, if any.
+		albumId     string
+		want        string
+		wantUploads int
+	}{
+		{"Should upload and record new content", "", "", mocks.UploadToken + "Id", 1},
+		{"Should return the media item of uploaded content", "fooId-0", "", "fooId-0", 0},
+		{"Should add the media item of uploaded content to the album", "fooId-0", "fooId-1", "fooId-0", 0},
+		{"Should upload again when the media item does not exist", "non-existent", "", mocks.UploadToken + "Id", 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client.UploadIndex = gphotos.NewMemoryUploadIndex()
+			if tc.indexed != "" {
+				_ = client.UploadIndex.Set(hash, tc.indexed)
+			}
+			counter.uploads = 0
+
+			var mediaItem *media_items.MediaItem
+			if tc.albumId != "" {
+				mediaItem, err = client.UploadToAlbum(context.Background(), tc.albumId, "testdata/upload-success")
+			} else {
+				mediaItem, err = client.Upload(context.Background(), "testdata/upload-success")
+			}
+			if err != nil {
+				t.Fatalf("error was not expected at this point: %s", err)
+			}
+			if tc.want != mediaItem.ID {
+				t.Errorf("want: %s, got: %s", tc.want, mediaItem.ID)
+			}
+			if tc.wantUploads != counter.uploads {
+				t.Errorf("want: %d uploads, got: %d", tc.wantUploads, counter.uploads)
+			}
+			if got, _ := client.UploadIndex.Get(hash); tc.want != got {
+				t.Errorf("want: %s indexed, got: %s", tc.want, got)
+			}
+		})
+	}
+
+	content, err := os.ReadFile("testdata/upload-success")
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	t.Run("Should use the index with every upload method", func(t *testing.T) {
+		uploads := map[string]func() (*media_items.MediaItem, error){
+			"UploadToAlbumAtPosition": func() (*media_items.MediaItem, error) {
+				return client.UploadToAlbumAtPosition(context.Background(), "fooId-1", "testdata/upload-success", albums.FirstInAlbum())
+			},
+			"UploadFromReader": func() (*media_items.MediaItem, error) {
+				return client.UploadFromReader(context.Background(), bytes.NewReader(content), int64(len(content)), "upload-success")
+			},
+			"UploadFromReader with a stream": func() (*media_items.MediaItem, error) {
+				return client.UploadFromReader(context.Background(), bytes.NewBuffer(content), int64(len(content)), "upload-success")
+			},
+			"UploadFromReaderToAlbum": func() (*media_items.MediaItem, error) {
+				return client.UploadFromReaderToAlbum(context.Background(), "fooId-1", bytes.NewReader(content), int64(len(content)), "upload-success")
+			},
+			"UploadMany": func() (*media_items.MediaItem, error) {
+				results, err := client.UploadMany(context.Background(), []string{"testdata/upload-success"}, gphotos.UploadManyOptions{})
+				if err != nil {
+					return nil, err
+				}
+				return results[0].MediaItem, results[0].Err
+			},
+		}
+		for name, upload := range uploads {
+			client.UploadIndex = gphotos.NewMemoryUploadIndex()
+			counter.uploads = 0
+
+			mediaItem, err := upload()
+			if err != nil {
+				t.Fatalf("%s: error was not expected at this point: %s", name, err)
+			}
+			if got, _ := client.UploadIndex.Get(hash); mediaItem.ID != got {
+				t.Errorf("%s: want: %s indexed, got: %s", name, mediaItem.ID, got)
+			}
+
+			_ = client.UploadIndex.Set(hash, "fooId-0")
+			mediaItem, err = upload()
+			if err != nil {
+				t.Fatalf("%s: error was not expected at this point: %s", name, err)
+			}
+			// Streams can only be read once, so they're always uploaded.
+			wantUploads, wantId := 1, "fooId-0"
+			if name == "UploadFromReader with a stream" {
+				wantUploads, wantId = 2, mocks.UploadToken+"Id"
+			}
+			if wantId != mediaItem.ID {
+				t.Errorf("%s: want: %s, got: %s", name, wantId, mediaItem.ID)
+			}
+			if wantUploads != counter.uploads {
+				t.Errorf("%s: want: %d uploads, got: %d", name, wantUploads, counter.uploads)
+			}
+		}
+	})
+
+	t.Run("Should return the media item when it can't be recorded", func(t *testing.T) {
+		client.UploadIndex = failingUploadIndex{gphotos.NewMemoryUploadIndex()}
+
+		mediaItem, err := client.Upload(context.Background(), "testdata/upload-success")
+		var indexErr *gphotos.UploadIndexError
+		if !errors.As(err, &indexErr) {
+			t.Fatalf("want: *UploadIndexError, got: %v", err)
+		}
+		if mediaItem != nil {
+			t.Errorf("want: nil media item, got: %v", mediaItem)
+		}
+		if want := mocks.UploadToken + "Id"; want != indexErr.MediaItem.ID {
+			t.Errorf("want: %s, got: %s", want, indexErr.MediaItem.ID)
+		}
+	})
+}
+
+// countingUploader counts the uploaded files and readers.
+type countingUploader struct {
+	gphotos.MediaUploader
+	uploads int
+}
+
+func (u *countingUploader) UploadFile(ctx context.Context, filePath string) (string, error) {
+	u.uploads++
+	return u.MediaUploader.UploadFile(ctx, filePath)
+}
+
+func (u *countingUploader) UploadReader(ctx context.Context, r io.Reader, size int64, name string) (string, error) {
+	u.uploads++
+	return u.MediaUploader.UploadReader(ctx, r, size, name)
+}
+
+// failingUploadIndex fails to record media items.
+type failingUploadIndex struct {
+	gphotos.UploadIndex
+}
+
+func (failingUploadIndex) Set(string, string) error {
+	return errors.New("index is read-only")
+}
+
+func TestClient_UploadToAlbumAtPosition(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	httpClient := http.DefaultClient
+
+	mockedUploader, err := uploader.NewSimpleUploader(httpClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	mockedUploader.BaseURL = srv.URL() + "/v1/uploads"
+
+	mediaItemsConfig := media_items.Config{
+		Client:  httpClient,
+		BaseURL: srv.URL(),
+	}
+	mockedMediaItems, err := media_items.New(mediaItemsConfig)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	client, err := gphotos.NewClient(httpClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	client.Uploader = mockedUploader
+	client.MediaItems = mockedMediaItems
+
+	t.Run("Should add the media item at the position", func(t *testing.T) {
+		mediaItem, err := client.UploadToAlbumAtPosition(context.Background(), "fooAlbum", "testdata/upload-success", albums.FirstInAlbum())
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if got := srv.AlbumMediaItems("fooAlbum")[0]; mediaItem.ID != got {
+			t.Errorf("want: %s, got: %s", mediaItem.ID, got)
+		}
+	})
+
+	t.Run("Should fail with an invalid position", func(t *testing.T) {
+		_, err := client.UploadToAlbumAtPosition(context.Background(), "fooAlbum", "testdata/upload-success", albums.AfterMediaItem(""))
+		if err == nil {
+			t.Errorf("error was expected but not produced")
+		}
+	})
+
+	t.Run("Should fail with a position without album", func(t *testing.T) {
+		_, err := client.UploadToAlbumAtPosition(context.Background(), "", "testdata/upload-success", albums.FirstInAlbum())
+		if err == nil {
+			t.Errorf("error was expected but not produced")
+		}
+	})
+}
+
+func TestClient_UploadFromReader(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	httpClient := http.DefaultClient
+
+	mockedUploader, err := uploader.NewSimpleUploader(httpClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	mockedUploader.BaseURL = srv.URL() + "/v1/uploads"
+
+	mediaItemsConfig := media_items.Config{
+		Client:  httpClient,
+		BaseURL: srv.URL(),
+	}
+	mockedMediaItems, err := media_items.New(mediaItemsConfig)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	client, err := gphotos.NewClient(httpClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	client.Uploader = mockedUploader
+	client.MediaItems = mockedMediaItems
+
+	// Starts like a JPEG file.
+	content := "\xFF\xD8\xFF foo bar baz"
+
+	t.Run("Should success with valid reader", func(t *testing.T) {
+		mediaItem, err := client.UploadFromReader(context.Background(), bytes.NewBufferString(content), int64(len(content)), "foo.jpg")
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if "foo.jpg" != mediaItem.Filename {
+			t.Errorf("want: %s, got: %s", "foo.jpg", mediaItem.Filename)
+		}
+	})
+
+	t.Run("Should add the media item to the album", func(t *testing.T) {
+		mediaItem, err := client.UploadFromReaderToAlbum(context.Background(), "fooAlbum", strings.NewReader(content), int64(len(content)), "foo.jpg")
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if got := srv.AlbumMediaItems("fooAlbum"); mediaItem.ID != got[len(got)-1] {
+			t.Errorf("want: %s, got: %s", mediaItem.ID, got[len(got)-1])
+		}
+	})
+
+	t.Run("Should fail when the upload fails", func(t *testing.T) {
+		_, err := client.UploadFromReader(context.Background(), strings.NewReader(content), int64(len(content)), mocks.UploadShouldFail)
+		if err == nil {
+			t.Errorf("error was expected but not produced")
+		}
+	})
+	t.Run("Should fail with unsupported media types", func(t *testing.T) {
+		_, err := client.UploadFromReader(context.Background(), strings.NewReader("foo bar baz"), 11, "foo.txt")
+		if !errors.Is(err, uploader.ErrUnsupportedMediaType) {
+			t.Errorf("want: %v, got: %v", uploader.ErrUnsupportedMediaType, err)
+		}
+	})
+}
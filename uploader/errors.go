@@ -2,9 +2,39 @@ package uploader
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
 	ErrUploadNotFound    = errors.New("upload not found")
 	ErrFingerprintNotSet = errors.New("fingerprint not set")
+
+	// ErrUploadURLMissing is returned when the server starts a resumable upload without
+	// sending the URL of the upload session in the X-Goog-Upload-URL header.
+	ErrUploadURLMissing = errors.New("upload URL missing in the response")
+
+	// ErrUploadNotSeekable is returned when a non-seekable upload needs to go back to content already sent.
+	ErrUploadNotSeekable = errors.New("upload is not seekable")
+
+	// ErrUnsupportedMediaType is returned when uploading a file that is not a photo or a video
+	// supported by Google Photos. See UnsupportedMediaTypeError.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
 )
+
+// UnsupportedMediaTypeError is the error returned when uploading a file that is not a photo or
+// a video supported by Google Photos. It matches ErrUnsupportedMediaType.
+type UnsupportedMediaTypeError struct {
+	// Name of the file.
+	Name string
+
+	// ContentType is the type of the file, as detected by http.DetectContentType.
+	ContentType string
+}
+
+func (e *UnsupportedMediaTypeError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrUnsupportedMediaType, e.Name, e.ContentType)
+}
+
+func (e *UnsupportedMediaTypeError) Is(target error) bool {
+	return target == ErrUnsupportedMediaType
+}
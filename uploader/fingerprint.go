@@ -0,0 +1,81 @@
+package uploader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/utils"
+)
+
+// Fingerprinter computes the fingerprint of a file. Resumable uploads use it to find
+// the upload URL of previous attempts to upload the same file in a Store.
+type Fingerprinter interface {
+	// Fingerprint returns the fingerprint of the file with the given info and content.
+	// The content is read from its current position, and it's not rewound.
+	Fingerprint(r io.Reader, info fs.FileInfo) (string, error)
+}
+
+// MetadataFingerprinter fingerprints files by name, size and modification time, without
+// reading them. It's the default one: touching or copying a file changes its fingerprint,
+// and files with the same metadata have the same fingerprint.
+type MetadataFingerprinter struct{}
+
+// Fingerprint implements Fingerprinter.
+func (MetadataFingerprinter) Fingerprint(_ io.Reader, info fs.FileInfo) (string, error) {
+	return fmt.Sprintf("%s-%d-%s", info.Name(), info.Size(), info.ModTime()), nil
+}
+
+// SHA256Fingerprinter fingerprints files by the SHA-256 hash of their content, which is
+// streamed, not loaded in memory. Files with the same content have the same fingerprint.
+type SHA256Fingerprinter struct{}
+
+// Fingerprint implements Fingerprinter.
+func (SHA256Fingerprinter) Fingerprint(r io.Reader, _ fs.FileInfo) (string, error) {
+	h := NewContentHash()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("hashing content: %w", err)
+	}
+	return ContentHashSum(h), nil
+}
+
+// XXHashFingerprinter fingerprints files by the xxHash (XXH64) hash of their content, like
+// SHA256Fingerprinter does, but faster. Its fingerprints are not cryptographically secure:
+// use it when files can't be forged to collide with each other.
+type XXHashFingerprinter struct{}
+
+// Fingerprint implements Fingerprinter.
+func (XXHashFingerprinter) Fingerprint(r io.Reader, _ fs.FileInfo) (string, error) {
+	h := xxhash.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("hashing content: %w", err)
+	}
+	return "xxh64:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// NewContentHash returns a new hash of content, as used by SHA256Fingerprinter and HashFile.
+// Use ContentHashSum to get the fingerprint of the content written to it.
+func NewContentHash() hash.Hash {
+	return sha256.New()
+}
+
+// ContentHashSum returns the fingerprint of the content written to h, which must have
+// been returned by NewContentHash.
+func ContentHashSum(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// HashFile returns the SHA-256 fingerprint of the file at filePath. See SHA256Fingerprinter.
+func HashFile(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer utils.CloseOrLog(f, filePath)
+	return SHA256Fingerprinter{}.Fingerprint(f, nil)
+}
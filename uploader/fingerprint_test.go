@@ -0,0 +1,119 @@
+package uploader_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+)
+
+func TestSHA256Fingerprinter(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string, modTime time.Time) os.FileInfo {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		return fi
+	}
+	now := time.Now()
+	original := writeFile("foo.jpg", "foo", now)
+	copied := writeFile("bar.jpg", "foo", now.Add(time.Hour))
+	other := writeFile("baz.jpg", "bar", now)
+
+	testCases := []struct {
+		name          string
+		fingerprinter uploader.Fingerprinter
+		a, b          os.FileInfo
+		contentA      string
+		contentB      string
+		wantEqual     bool
+	}{
+		{"Should match copies by content", uploader.SHA256Fingerprinter{}, original, copied, "foo", "foo", true},
+		{"Should not match different content", uploader.SHA256Fingerprinter{}, original, other, "foo", "bar", false},
+		{"Should match copies by xxHash", uploader.XXHashFingerprinter{}, original, copied, "foo", "foo", true},
+		{"Should not match different content by xxHash", uploader.XXHashFingerprinter{}, original, other, "foo", "bar", false},
+		{"Should not match copies by metadata", uploader.MetadataFingerprinter{}, original, copied, "foo", "foo", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := tc.fingerprinter.Fingerprint(strings.NewReader(tc.contentA), tc.a)
+			if err != nil {
+				t.Fatalf("error was not expected at this point: %s", err)
+			}
+			b, err := tc.fingerprinter.Fingerprint(strings.NewReader(tc.contentB), tc.b)
+			if err != nil {
+				t.Fatalf("error was not expected at this point: %s", err)
+			}
+			if tc.wantEqual != (a == b) {
+				t.Errorf("want equal: %t, got: %s and %s", tc.wantEqual, a, b)
+			}
+		})
+	}
+
+	t.Run("Should hash a file", func(t *testing.T) {
+		got, err := uploader.HashFile(filepath.Join(dir, "foo.jpg"))
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		want := "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+		if want != got {
+			t.Errorf("want: %s, got: %s", want, got)
+		}
+	})
+
+	t.Run("Should hash content like files", func(t *testing.T) {
+		h := uploader.NewContentHash()
+		_, _ = h.Write([]byte("foo"))
+		want := "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+		if got := uploader.ContentHashSum(h); want != got {
+			t.Errorf("want: %s, got: %s", want, got)
+		}
+	})
+
+	t.Run("Should fail if file doesn't exist", func(t *testing.T) {
+		if _, err := uploader.HashFile(filepath.Join(dir, "non-existent")); err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+
+	t.Run("Should fail when the content can't be read", func(t *testing.T) {
+		if _, err := (uploader.SHA256Fingerprinter{}).Fingerprint(&streamReader{size: 1000, failAt: 500}, original); err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+}
+
+func TestResumableUploader_Fingerprinter(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	u, err := uploader.NewResumableUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point, err: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+	u.Store = uploader.NewMemoryStore()
+	u.Fingerprinter = uploader.SHA256Fingerprinter{}
+
+	got, err := u.UploadFile(context.Background(), "testdata/upload-success")
+	if err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	if mocks.UploadToken != got {
+		t.Errorf("want: %s, got: %s", mocks.UploadToken, got)
+	}
+}
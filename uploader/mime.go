@@ -0,0 +1,221 @@
+package uploader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// sniffLen is the number of bytes read to detect the content type of uploads.
+const sniffLen = 512
+
+// extensionTypes are the MIME types of the file extensions supported by Google Photos.
+//
+// See: https://support.google.com/googlephotos/answer/6193313.
+var extensionTypes = map[string]string{
+	// Photos
+	".avif": "image/avif",
+	".bmp":  "image/bmp",
+	".gif":  "image/gif",
+	".heic": "image/heic",
+	".heif": "image/heif",
+	".ico":  "image/x-icon",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+	".webp": "image/webp",
+
+	// RAW photos
+	".arw": "image/x-sony-arw",
+	".cr2": "image/x-canon-cr2",
+	".cr3": "image/x-canon-cr3",
+	".dng": "image/x-adobe-dng",
+	".nef": "image/x-nikon-nef",
+	".orf": "image/x-olympus-orf",
+	".raf": "image/x-fuji-raf",
+	".rw2": "image/x-panasonic-rw2",
+
+	// Videos
+	".3g2":  "video/3gpp2",
+	".3gp":  "video/3gpp",
+	".asf":  "video/x-ms-asf",
+	".avi":  "video/x-msvideo",
+	".divx": "video/x-msvideo",
+	".m2t":  "video/mp2t",
+	".m2ts": "video/mp2t",
+	".m4v":  "video/x-m4v",
+	".mkv":  "video/x-matroska",
+	".mmv":  "video/mp2t",
+	".mod":  "video/mpeg",
+	".mov":  "video/quicktime",
+	".mp4":  "video/mp4",
+	".mpg":  "video/mpeg",
+	".mts":  "video/mp2t",
+	".tod":  "video/mpeg",
+	".wmv":  "video/x-ms-wmv",
+}
+
+// SupportedExtensions returns the file extensions of the photos and videos supported by
+// Google Photos, sorted. They're lowercase, and start with a dot.
+func SupportedExtensions() []string {
+	return slices.Sorted(maps.Keys(extensionTypes))
+}
+
+// refinedTypes are the types detected by content that are shared by more specific formats.
+// The type of the file extension is used instead, when it's one of them.
+var refinedTypes = map[string][]string{
+	// RAW formats are TIFF files.
+	"image/tiff": {"image/x-sony-arw", "image/x-canon-cr2", "image/x-adobe-dng", "image/x-nikon-nef"},
+	"video/mp4":  {"video/x-m4v"},
+	// WMV files are ASF files.
+	"video/x-ms-asf": {"video/x-ms-wmv"},
+}
+
+// signature is the magic number of a format: the given bytes at the given offset.
+type signature struct {
+	offset      int
+	magic       []byte
+	contentType string
+}
+
+// signatures are the magic numbers of the formats supported by Google Photos.
+// ISO base media files, like MP4 or HEIC, are detected by their brand. See isoBrandTypes.
+var signatures = []signature{
+	{0, []byte("\xFF\xD8\xFF"), "image/jpeg"},
+	{0, []byte("\x89PNG\r\n\x1A\n"), "image/png"},
+	{0, []byte("GIF87a"), "image/gif"},
+	{0, []byte("GIF89a"), "image/gif"},
+	{8, []byte("WEBP"), "image/webp"},
+	{8, []byte("AVI "), "video/x-msvideo"},
+	{0, []byte("BM"), "image/bmp"},
+	{0, []byte("\x00\x00\x01\x00"), "image/x-icon"},
+	{0, []byte("FUJIFILMCCD-RAW"), "image/x-fuji-raf"},
+	{0, []byte("IIRO"), "image/x-olympus-orf"},
+	{0, []byte("IIRS"), "image/x-olympus-orf"},
+	{0, []byte("MMOR"), "image/x-olympus-orf"},
+	{0, []byte("IIU\x00"), "image/x-panasonic-rw2"},
+	{8, []byte("CR\x02"), "image/x-canon-cr2"},
+	{0, []byte("II*\x00"), "image/tiff"},
+	{0, []byte("MM\x00*"), "image/tiff"},
+	{0, []byte("\x1A\x45\xDF\xA3"), "video/x-matroska"},
+	{0, []byte("\x30\x26\xB2\x75\x8E\x66\xCF\x11"), "video/x-ms-asf"},
+	{0, []byte("\x00\x00\x01\xBA"), "video/mpeg"},
+}
+
+// isoBrandTypes are the types of ISO base media files by their major brand.
+// Files with other brands are not recognized by their content.
+var isoBrandTypes = map[string]string{
+	"avif": "image/avif",
+	"avis": "image/avif",
+	"heic": "image/heic",
+	"heix": "image/heic",
+	"heim": "image/heic",
+	"heis": "image/heic",
+	"hevc": "image/heic-sequence",
+	"hevx": "image/heic-sequence",
+	"mif1": "image/heif",
+	"msf1": "image/heif-sequence",
+	"crx ": "image/x-canon-cr3",
+	"qt  ": "video/quicktime",
+	"M4V ": "video/x-m4v",
+	"M4VH": "video/x-m4v",
+	"M4VP": "video/x-m4v",
+	"3g2a": "video/3gpp2",
+	"3g2b": "video/3gpp2",
+	"3g2c": "video/3gpp2",
+	"isom": "video/mp4",
+	"iso2": "video/mp4",
+	"iso4": "video/mp4",
+	"iso5": "video/mp4",
+	"iso6": "video/mp4",
+	"mp41": "video/mp4",
+	"mp42": "video/mp4",
+	"avc1": "video/mp4",
+	"dash": "video/mp4",
+	"mmp4": "video/mp4",
+	"MSNV": "video/mp4",
+	"f4v ": "video/mp4",
+	"XAVC": "video/mp4",
+}
+
+// DetectContentType returns the MIME type of a file with the given name, whose content
+// starts with header. At most the first 512 bytes of header are considered.
+//
+// The type is detected by the magic number of the content, falling back to the file
+// extension. Returns an *UnsupportedMediaTypeError, matching ErrUnsupportedMediaType,
+// if the file is not a photo or a video supported by Google Photos.
+func DetectContentType(header []byte, name string) (string, error) {
+	header = header[:min(len(header), sniffLen)]
+	extType := extensionTypes[strings.ToLower(filepath.Ext(name))]
+
+	contentType := sniffContentType(header)
+	if contentType == "" {
+		contentType = extType
+	} else if slices.Contains(refinedTypes[contentType], extType) {
+		contentType = extType
+	}
+	if contentType == "" {
+		return "", &UnsupportedMediaTypeError{Name: name, ContentType: http.DetectContentType(header)}
+	}
+	return contentType, nil
+}
+
+// sniffContentType returns the MIME type of the content starting with header, or an empty
+// string if it's not recognized.
+func sniffContentType(header []byte) string {
+	// ISO base media files start with a 'ftyp' box with the major brand.
+	if len(header) >= 12 && string(header[4:8]) == "ftyp" {
+		brand := string(header[8:12])
+		if t, ok := isoBrandTypes[brand]; ok {
+			return t
+		}
+		if strings.HasPrefix(brand, "3gp") {
+			return "video/3gpp"
+		}
+		// Unknown brands, like those of other ISO base media formats, fall back to the extension.
+		return ""
+	}
+	for _, s := range signatures {
+		if len(header) >= s.offset+len(s.magic) && bytes.Equal(header[s.offset:s.offset+len(s.magic)], s.magic) {
+			return s.contentType
+		}
+	}
+	// MPEG transport streams are made of 188-byte packets starting with 0x47,
+	// prefixed by a 4-byte timestamp in M2TS files.
+	for _, start := range []int{0, 4} {
+		if len(header) > start+188 && header[start] == 0x47 && header[start+188] == 0x47 {
+			return "video/mp2t"
+		}
+	}
+	return ""
+}
+
+// detectContentType sets the ContentType of the upload, reading the beginning of its content.
+// Non-seekable streams keep the bytes read in memory to send them later.
+func (u *Upload) detectContentType() error {
+	header := make([]byte, min(sniffLen, u.size))
+	if rs, ok := u.stream.(io.ReadSeeker); ok {
+		if _, err := io.ReadFull(newSection(rs, 0, u.size), header); err != nil {
+			return fmt.Errorf("reading upload: %w", err)
+		}
+	} else {
+		if _, err := io.ReadFull(u.stream, header); err != nil {
+			return fmt.Errorf("reading upload: %w", err)
+		}
+		u.stream = io.MultiReader(bytes.NewReader(header), u.stream)
+	}
+
+	contentType, err := DetectContentType(header, u.Name)
+	if err != nil {
+		return err
+	}
+	u.ContentType = contentType
+	return nil
+}
@@ -0,0 +1,138 @@
+package uploader_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+)
+
+func TestDetectContentType(t *testing.T) {
+	readHeader := func(path string) []byte {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		return b[:512]
+	}
+	ftyp := func(brand string) []byte {
+		return []byte("\x00\x00\x00\x18ftyp" + brand + "\x00\x00\x00\x00")
+	}
+	transportStream := make([]byte, 2*188)
+	transportStream[0], transportStream[188] = 0x47, 0x47
+
+	testCases := []struct {
+		name        string
+		header      []byte
+		fileName    string
+		want        string
+		errExpected bool
+	}{
+		{"Should detect JPEG", readHeader("testdata/file_example_JPG_100kB.jpg"), "foo", "image/jpeg", false},
+		{"Should detect PNG", readHeader("testdata/file_example_PNG_500kB.png"), "foo", "image/png", false},
+		{"Should detect WEBP", readHeader("testdata/file_example_WEBP_50kB.webp"), "foo", "image/webp", false},
+		{"Should detect by content over extension", readHeader("testdata/file_example_PNG_500kB.png"), "foo.jpg", "image/png", false},
+		{"Should detect HEIC", ftyp("heic"), "foo", "image/heic", false},
+		{"Should detect AVIF", ftyp("avif"), "foo", "image/avif", false},
+		{"Should detect CR3", ftyp("crx "), "foo", "image/x-canon-cr3", false},
+		{"Should detect MP4", ftyp("isom"), "foo", "video/mp4", false},
+		{"Should detect M4V by extension", ftyp("isom"), "foo.m4v", "video/x-m4v", false},
+		{"Should detect MP4 brands", ftyp("mp42"), "foo", "video/mp4", false},
+		{"Should detect unknown brands by extension", ftyp("foo "), "foo.mov", "video/quicktime", false},
+		{"Should fail with unknown brands without extension", ftyp("foo "), "foo", "", true},
+		{"Should detect MOV", ftyp("qt  "), "foo", "video/quicktime", false},
+		{"Should detect 3GP", ftyp("3gp5"), "foo", "video/3gpp", false},
+		{"Should detect MKV", []byte("\x1A\x45\xDF\xA3\x01\x00\x00\x00"), "foo", "video/x-matroska", false},
+		{"Should detect AVI", []byte("RIFF\x00\x00\x00\x00AVI LIST"), "foo", "video/x-msvideo", false},
+		{"Should detect MPEG transport streams", transportStream, "foo", "video/mp2t", false},
+		{"Should detect CR2", []byte("II*\x00\x10\x00\x00\x00CR\x02\x00"), "foo", "image/x-canon-cr2", false},
+		{"Should detect RAF", []byte("FUJIFILMCCD-RAW 0201"), "foo", "image/x-fuji-raf", false},
+		{"Should detect TIFF", []byte("II*\x00\x08\x00\x00\x00"), "foo", "image/tiff", false},
+		{"Should detect TIFF based RAW by extension", []byte("II*\x00\x08\x00\x00\x00"), "foo.NEF", "image/x-nikon-nef", false},
+		{"Should detect by extension", []byte("foo bar baz"), "foo.mp4", "video/mp4", false},
+		{"Should detect empty files by extension", nil, "foo.jpg", "image/jpeg", false},
+		{"Should fail with unsupported content and extension", []byte("foo bar baz"), "foo.txt", "", true},
+		{"Should fail with unsupported content without extension", []byte("%PDF-1.7"), "foo", "", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := uploader.DetectContentType(tc.header, tc.fileName)
+			if tc.errExpected {
+				if !errors.Is(err, uploader.ErrUnsupportedMediaType) {
+					t.Fatalf("want: %v, got: %v", uploader.ErrUnsupportedMediaType, err)
+				}
+				var typeErr *uploader.UnsupportedMediaTypeError
+				if !errors.As(err, &typeErr) || tc.fileName != typeErr.Name {
+					t.Errorf("want: *UnsupportedMediaTypeError for %s, got: %v", tc.fileName, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if tc.want != got {
+				t.Errorf("want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSupportedExtensions(t *testing.T) {
+	got := uploader.SupportedExtensions()
+	if !slices.IsSorted(got) {
+		t.Errorf("want: sorted extensions, got: %v", got)
+	}
+	for _, ext := range []string{".jpg", ".heic", ".avif", ".dng", ".mp4", ".mov", ".mkv"} {
+		if !slices.Contains(got, ext) {
+			t.Errorf("want: %s in %v", ext, got)
+		}
+	}
+}
+
+func TestUploader_UnsupportedMediaType(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "foo.txt")
+	if err := os.WriteFile(path, []byte("foo bar baz"), 0o600); err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	simple, err := uploader.NewSimpleUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	simple.BaseURL = srv.URL() + "/v1/uploads"
+	resumable, err := uploader.NewResumableUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	resumable.BaseURL = srv.URL() + "/v1/uploads"
+
+	for name, u := range map[string]interface {
+		UploadFile(ctx context.Context, filePath string) (string, error)
+		UploadReader(ctx context.Context, r io.Reader, size int64, name string) (string, error)
+	}{"SimpleUploader": simple, "ResumableUploader": resumable} {
+		t.Run(name+" should reject unsupported files", func(t *testing.T) {
+			_, err := u.UploadFile(context.Background(), path)
+			if !errors.Is(err, uploader.ErrUnsupportedMediaType) {
+				t.Errorf("want: %v, got: %v", uploader.ErrUnsupportedMediaType, err)
+			}
+		})
+
+		t.Run(name+" should reject unsupported readers", func(t *testing.T) {
+			_, err := u.UploadReader(context.Background(), strings.NewReader("foo bar baz"), 11, "foo.txt")
+			if !errors.Is(err, uploader.ErrUnsupportedMediaType) {
+				t.Errorf("want: %v, got: %v", uploader.ErrUnsupportedMediaType, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package uploader
+
+import (
+	"io"
+	"time"
+)
+
+// Progress is the progress of an upload.
+type Progress struct {
+	// Name of the uploaded file.
+	Name string
+
+	// Sent is the number of bytes sent, including the ones sent before resuming the upload.
+	Sent int64
+
+	// Total is the size of the upload.
+	Total int64
+
+	// Offset is where the upload was resumed from. It's 0 for uploads started from scratch.
+	Offset int64
+
+	// Throughput is the average number of bytes per second sent since the upload started or resumed.
+	Throughput float64
+}
+
+// ProgressFunc is called with the progress of an upload. It's called from the goroutine
+// sending the upload, so it should return quickly. Concurrent uploads call it concurrently.
+type ProgressFunc func(Progress)
+
+// progressInterval is the minimum time between two progress reports of the same upload.
+// The first report, the end of every request body and the completion of the upload are always reported.
+const progressInterval = 200 * time.Millisecond
+
+// progressReporter reports the progress of an upload.
+type progressReporter struct {
+	fn        ProgressFunc
+	progress  Progress
+	start     time.Time
+	last      time.Time
+	completed bool
+}
+
+func newProgressReporter(fn ProgressFunc, upload *Upload) *progressReporter {
+	if fn == nil {
+		return nil
+	}
+	return &progressReporter{
+		fn:       fn,
+		progress: Progress{Name: upload.Name, Total: upload.size},
+		start:    time.Now(),
+	}
+}
+
+// resumed sets the offset where the upload was resumed from.
+func (p *progressReporter) resumed(offset int64) {
+	if p == nil {
+		return
+	}
+	p.progress.Offset = offset
+	p.progress.Sent = offset
+	p.start = time.Now()
+}
+
+// report reports that sent bytes have been sent. Reports are throttled, unless force is set.
+func (p *progressReporter) report(sent int64, force bool) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	p.progress.Sent = sent
+	if !force && sent < p.progress.Total && !p.last.IsZero() && now.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = now
+	p.completed = sent == p.progress.Total
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		p.progress.Throughput = float64(sent-p.progress.Offset) / elapsed
+	}
+	p.fn(p.progress)
+}
+
+// finish reports the completion of the upload, if it hasn't been reported yet.
+// Empty uploads don't send any bytes, for example.
+func (p *progressReporter) finish() {
+	if p == nil || p.completed {
+		return
+	}
+	p.report(p.progress.Total, true)
+}
+
+// progressBody reports the bytes read from a request body of length bytes, which starts at offset in the upload.
+type progressBody struct {
+	io.ReadSeeker
+	reporter       *progressReporter
+	offset, length int64
+	pos            int64
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadSeeker.Read(p)
+	if n > 0 {
+		b.pos += int64(n)
+		b.reporter.report(b.offset+b.pos, b.pos == b.length)
+	}
+	return n, err
+}
+
+func (b *progressBody) Seek(offset int64, whence int) (int64, error) {
+	pos, err := b.ReadSeeker.Seek(offset, whence)
+	if err == nil {
+		b.pos = pos
+	}
+	return pos, err
+}
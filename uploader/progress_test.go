@@ -0,0 +1,115 @@
+package uploader_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+)
+
+func TestSimpleUploader_OnProgress(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	u, err := uploader.NewSimpleUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+	var got []uploader.Progress
+	u.OnProgress = func(p uploader.Progress) { got = append(got, p) }
+
+	const size = 1 << 20
+	if _, err := u.UploadReader(context.Background(), &streamReader{size: size}, size, "foo.mp4"); err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+	assertProgress(t, got, "foo.mp4", size, 0)
+}
+
+func TestResumableUploader_OnProgress(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	// Four full chunks and a partial one.
+	const chunkSize = 256 << 10
+	const size = 4*chunkSize + 100
+	path := filepath.Join(t.TempDir(), "foo.mp4")
+	if err := os.WriteFile(path, bytes.Repeat([]byte{'f'}, size), 0o600); err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+
+	u, err := uploader.NewResumableUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+	u.ChunkSize = chunkSize
+	u.Store = uploader.NewMemoryStore()
+	u.OnProgress = func(p uploader.Progress) {
+		t.Errorf("want: progress reported to the upload options, got: %v", p)
+	}
+
+	t.Run("Should report progress until the upload is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var got []uploader.Progress
+		options := uploader.UploadOptions{OnProgress: func(p uploader.Progress) {
+			got = append(got, p)
+			// Cancel once the second chunk has been sent: the server has received one or two chunks.
+			if p.Sent > chunkSize {
+				cancel()
+			}
+		}}
+		if _, err := u.UploadFileWithOptions(ctx, path, options); err == nil {
+			t.Fatalf("error was expected, but not produced")
+		}
+		if len(got) == 0 {
+			t.Errorf("want: progress reported, got: none")
+		}
+	})
+
+	t.Run("Should report the offset when resuming the upload", func(t *testing.T) {
+		var got []uploader.Progress
+		options := uploader.UploadOptions{OnProgress: func(p uploader.Progress) { got = append(got, p) }}
+		if _, err := u.UploadFileWithOptions(context.Background(), path, options); err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if len(got) == 0 {
+			t.Fatalf("want: progress reported, got: none")
+		}
+		offset := got[0].Offset
+		if offset == 0 || offset%chunkSize != 0 {
+			t.Errorf("want: upload resumed at the end of a chunk, got: offset %d", offset)
+		}
+		assertProgress(t, got, "foo.mp4", size, offset)
+	})
+}
+
+func assertProgress(t *testing.T, got []uploader.Progress, wantName string, wantTotal int64, wantOffset int64) {
+	t.Helper()
+	if len(got) == 0 {
+		t.Fatalf("want: progress reported, got: none")
+	}
+	var sent int64
+	for _, p := range got {
+		if wantName != p.Name || wantTotal != p.Total || wantOffset != p.Offset {
+			t.Errorf("want: %s, %d bytes, offset %d, got: %+v", wantName, wantTotal, wantOffset, p)
+		}
+		if p.Sent < sent {
+			t.Errorf("want: increasing bytes sent, got: %d after %d", p.Sent, sent)
+		}
+		sent = p.Sent
+	}
+	last := got[len(got)-1]
+	if wantTotal != last.Sent {
+		t.Errorf("want: %d bytes sent, got: %d", wantTotal, last.Sent)
+	}
+	if last.Throughput <= 0 {
+		t.Errorf("want: throughput, got: %f", last.Throughput)
+	}
+}
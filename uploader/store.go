@@ -0,0 +1,247 @@
+package uploader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/filelock"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/internal/log"
+)
+
+// UploadSessionLifetime is how long an upload URL can be used to resume an upload.
+// Stores don't return upload URLs older than this.
+//
+// See: https://developers.google.com/photos/library/guides/resumable-uploads.
+const UploadSessionLifetime = 7 * 24 * time.Hour
+
+// storeEntry is an upload URL and the time it was stored.
+type storeEntry struct {
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (e storeEntry) expired(now time.Time) bool {
+	return now.Sub(e.CreatedAt) >= UploadSessionLifetime
+}
+
+// MemoryStore is a Store kept in memory. It's safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]storeEntry
+}
+
+// NewMemoryStore returns an empty in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]storeEntry)}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(fingerprint string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[fingerprint]
+	if !ok {
+		return "", false
+	}
+	if e.expired(time.Now()) {
+		delete(s.entries, fingerprint)
+		return "", false
+	}
+	return e.URL, true
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(fingerprint string, url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[fingerprint] = storeEntry{URL: url, CreatedAt: time.Now()}
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(fingerprint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, fingerprint)
+}
+
+// Close implements Store. It removes all the upload URLs.
+func (s *MemoryStore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.entries)
+}
+
+const (
+	// fileStoreName is the name of the FileStore file in its directory.
+	fileStoreName = "upload-sessions.json"
+
+	// fileStoreLockName is the name of the file locked while accessing the FileStore file.
+	fileStoreLockName = "upload-sessions.lock"
+)
+
+// FileStore is a Store persisted in a JSON file. It's safe for concurrent use by
+// multiple goroutines and, on Unix and Windows systems, by multiple processes sharing the directory.
+//
+// The file is replaced atomically on every change, so it's never left half-written.
+// Store methods can't return errors, so they're logged: a failed Get returns no
+// upload URL, and failed changes are lost, making uploads start from scratch.
+type FileStore struct {
+	// Logger used to log errors.
+	Logger log.Logger
+
+	dir string
+
+	mu   sync.Mutex
+	lock *os.File // nil when closed.
+}
+
+// NewFileStore returns a Store persisted in the given directory, which is created if it doesn't exist.
+// The caller must close it. On systems other than Unix and Windows, files can't be locked, so
+// processes must not share the directory.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating store: %w", err)
+	}
+	lock, err := os.OpenFile(filepath.Join(dir, fileStoreLockName), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("creating store: %w", err)
+	}
+	return &FileStore{
+		Logger: &log.DiscardLogger{},
+		dir:    dir,
+		lock:   lock,
+	}, nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(fingerprint string) (string, bool) {
+	var e storeEntry
+	var ok bool
+	err := s.update(false, func(entries map[string]storeEntry) bool {
+		e, ok = entries[fingerprint]
+		return false
+	})
+	if err != nil {
+		s.Logger.Errorf("Error while getting upload URL from store: %s", err)
+		return "", false
+	}
+	if !ok || e.expired(time.Now()) {
+		return "", false
+	}
+	return e.URL, true
+}
+
+// Set implements Store. Expired upload URLs are removed.
+func (s *FileStore) Set(fingerprint string, url string) {
+	err := s.update(true, func(entries map[string]storeEntry) bool {
+		now := time.Now()
+		for k, e := range entries {
+			if e.expired(now) {
+				delete(entries, k)
+			}
+		}
+		entries[fingerprint] = storeEntry{URL: url, CreatedAt: now}
+		return true
+	})
+	if err != nil {
+		s.Logger.Errorf("Error while setting upload URL in store: %s", err)
+	}
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(fingerprint string) {
+	err := s.update(true, func(entries map[string]storeEntry) bool {
+		_, ok := entries[fingerprint]
+		delete(entries, fingerprint)
+		return ok
+	})
+	if err != nil {
+		s.Logger.Errorf("Error while deleting upload URL from store: %s", err)
+	}
+}
+
+// Close implements Store. It releases the store, keeping the upload URLs in the directory.
+func (s *FileStore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lock == nil {
+		return
+	}
+	if err := s.lock.Close(); err != nil {
+		s.Logger.Errorf("Error while closing store: %s", err)
+	}
+	s.lock = nil
+}
+
+// update reads the entries with the store locked, and writes them back if fn returns true.
+// The lock is exclusive when the entries are going to be written, and shared otherwise.
+func (s *FileStore) update(write bool, fn func(entries map[string]storeEntry) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lock == nil {
+		return errors.New("store is closed")
+	}
+	if err := filelock.Lock(s.lock, write); err != nil {
+		return err
+	}
+	defer func() {
+		if err := filelock.Unlock(s.lock); err != nil {
+			s.Logger.Errorf("Error while unlocking store: %s", err)
+		}
+	}()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	if !fn(entries) || !write {
+		return nil
+	}
+	return s.write(entries)
+}
+
+func (s *FileStore) read() (map[string]storeEntry, error) {
+	entries := make(map[string]storeEntry)
+	b, err := os.ReadFile(filepath.Join(s.dir, fileStoreName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", fileStoreName, err)
+	}
+	return entries, nil
+}
+
+// write replaces the store file atomically.
+func (s *FileStore) write(entries map[string]storeEntry) error {
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.dir, fileStoreName+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(s.dir, fileStoreName))
+}
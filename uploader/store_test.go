@@ -0,0 +1,157 @@
+package uploader_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, uploader.NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := uploader.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	testStore(t, s)
+
+	t.Run("Should keep upload URLs after closing", func(t *testing.T) {
+		s.Set("fooFingerprint", "fooURL")
+		s.Close()
+
+		s, err := uploader.NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		defer s.Close()
+		assertStoredURL(t, s, "fooFingerprint", "fooURL", true)
+	})
+
+	t.Run("Should not return expired upload URLs", func(t *testing.T) {
+		createdAt := time.Now().Add(-uploader.UploadSessionLifetime).Format(time.RFC3339Nano)
+		content := fmt.Sprintf(`{"fooExpired":{"url":"fooURL","createdAt":%q}}`, createdAt)
+		if err := os.WriteFile(filepath.Join(dir, "upload-sessions.json"), []byte(content), 0o600); err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+
+		s, err := uploader.NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		defer s.Close()
+		assertStoredURL(t, s, "fooExpired", "", false)
+	})
+
+	t.Run("Should be safe for concurrent use by many stores", func(t *testing.T) {
+		dir := t.TempDir()
+		var wg sync.WaitGroup
+		for i := range 4 {
+			s, err := uploader.NewFileStore(dir)
+			if err != nil {
+				t.Fatalf("error was not expected at this point: %s", err)
+			}
+			defer s.Close()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 10 {
+					s.Set(fmt.Sprintf("foo-%d-%d", i, j), "fooURL")
+				}
+			}()
+		}
+		wg.Wait()
+
+		s, err := uploader.NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		defer s.Close()
+		for i := range 4 {
+			for j := range 10 {
+				assertStoredURL(t, s, fmt.Sprintf("foo-%d-%d", i, j), "fooURL", true)
+			}
+		}
+	})
+
+	t.Run("Should not return upload URLs when closed", func(t *testing.T) {
+		s, err := uploader.NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("error was not expected at this point: %s", err)
+		}
+		s.Set("fooFingerprint", "fooURL")
+		s.Close()
+		assertStoredURL(t, s, "fooFingerprint", "", false)
+	})
+}
+
+func TestResumableUploader_FileStore(t *testing.T) {
+	s, err := uploader.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	defer s.Close()
+
+	u, err := uploader.NewResumableUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+	u.BaseURL = srv.URL() + "/v1/uploads"
+	u.Store = s
+
+	if _, err := u.UploadFile(context.Background(), "testdata/upload-success"); err != nil {
+		t.Fatalf("error was not expected, err: %s", err)
+	}
+}
+
+func testStore(t *testing.T, s uploader.Store) {
+	t.Helper()
+	t.Run("Should return stored upload URLs", func(t *testing.T) {
+		s.Set("fooFingerprint", "fooURL")
+		assertStoredURL(t, s, "fooFingerprint", "fooURL", true)
+		s.Set("fooFingerprint", "barURL")
+		assertStoredURL(t, s, "fooFingerprint", "barURL", true)
+	})
+
+	t.Run("Should not return deleted upload URLs", func(t *testing.T) {
+		s.Set("fooFingerprint", "fooURL")
+		s.Delete("fooFingerprint")
+		assertStoredURL(t, s, "fooFingerprint", "", false)
+		s.Delete("non-existent")
+	})
+
+	t.Run("Should be safe for concurrent use", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fingerprint := fmt.Sprintf("foo-%d", i)
+				s.Set(fingerprint, "fooURL")
+				s.Get(fingerprint)
+				s.Delete(fingerprint)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func assertStoredURL(t *testing.T, s uploader.Store, fingerprint string, wantURL string, wantFound bool) {
+	t.Helper()
+	got, found := s.Get(fingerprint)
+	if wantFound != found || wantURL != got {
+		t.Errorf("want: %q (found: %t), got: %q (found: %t)", wantURL, wantFound, got, found)
+	}
+}
@@ -2,48 +2,264 @@ package uploader
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 )
 
 type Upload struct {
-	stream io.ReadSeeker
+	stream io.Reader
 	size   int64
 
+	// spool keeps the last chunk read from non-seekable streams.
+	spool *spool
+
+	// progress reports the bytes sent, if set.
+	progress *progressReporter
+
 	Name        string
 	Fingerprint string
+
+	// ContentType is the MIME type of the content. It's detected by NewUploadFromFile.
+	// Uploads with an empty one are sent as application/octet-stream.
+	ContentType string
 }
 
 // NewUpload creates a new upload from an io.Reader.
-func NewUpload(reader io.Reader, size int64, name string, fingerprint string) *Upload {
-	stream, ok := reader.(io.ReadSeeker)
-
-	if !ok {
-		buf := new(bytes.Buffer)
-		_, err := buf.ReadFrom(reader)
+//
+// The reader is never loaded in memory. Readers implementing io.ReadSeeker are read
+// from any offset, starting at 0, and their size is checked. The rest of them are
+// streamed: raw uploads read them once, and resumable uploads keep only the chunk
+// being sent in memory.
+func NewUpload(reader io.Reader, size int64, name string, fingerprint string) (*Upload, error) {
+	if reader == nil {
+		return nil, errors.New("reader is nil")
+	}
+	if size < 0 {
+		return nil, errors.New("size is negative")
+	}
+	if rs, ok := reader.(io.ReadSeeker); ok {
+		end, err := rs.Seek(0, io.SeekEnd)
 		if err != nil {
-			return nil
+			return nil, err
+		}
+		if end != size {
+			return nil, fmt.Errorf("size is %d, but the reader has %d bytes", size, end)
 		}
-		stream = bytes.NewReader(buf.Bytes())
 	}
 	return &Upload{
-		stream: stream,
+		stream: reader,
 		size:   size,
 
 		Name:        name,
 		Fingerprint: fingerprint,
-	}
+	}, nil
 }
 
-// NewUploadFromFile creates a new Upload from an os.File.
+// NewUploadFromFile creates a new Upload from an os.File, fingerprinted by its metadata.
+// See MetadataFingerprinter.
+//
+// The content type is detected using DetectContentType, and an *UnsupportedMediaTypeError
+// is returned if the file is not a photo or a video supported by Google Photos.
 func NewUploadFromFile(f *os.File) (*Upload, error) {
+	return NewUploadFromFileWithFingerprinter(f, MetadataFingerprinter{})
+}
+
+// NewUploadFromFileWithFingerprinter creates a new Upload from an os.File, fingerprinted by fp.
+// See NewUploadFromFile.
+func NewUploadFromFileWithFingerprinter(f *os.File, fp Fingerprinter) (*Upload, error) {
 	fi, err := f.Stat()
 	if err != nil {
 		return nil, err
 	}
 
-	fingerprint := fmt.Sprintf("%s-%d-%s", fi.Name(), fi.Size(), fi.ModTime())
+	upload, err := NewUpload(f, fi.Size(), fi.Name(), "")
+	if err != nil {
+		return nil, err
+	}
+	// Unsupported files are rejected before fingerprinting them, which could read them.
+	if err := upload.detectContentType(); err != nil {
+		return nil, err
+	}
 
-	return NewUpload(f, fi.Size(), fi.Name(), fingerprint), nil
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	upload.Fingerprint, err = fp.Fingerprint(f, fi)
+	if err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// newUploadFromReader creates a new Upload, without fingerprint, from an io.Reader.
+// Its content type is detected like in NewUploadFromFile.
+func newUploadFromReader(r io.Reader, size int64, name string) (*Upload, error) {
+	if name == "" {
+		return nil, errors.New("name is empty")
+	}
+	upload, err := NewUpload(r, size, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := upload.detectContentType(); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// body returns the whole upload content as a request body.
+// Non-seekable streams can only be sent once.
+func (u *Upload) body() io.ReadCloser {
+	if rs, ok := u.stream.(io.ReadSeeker); ok {
+		return u.requestBody(newSection(rs, 0, u.size), 0, u.size)
+	}
+	return u.requestBody(&onceReader{r: u.stream}, 0, u.size)
+}
+
+// chunk returns length bytes of the upload content, starting at offset, as a request body.
+//
+// Non-seekable streams are spooled: the chunk is kept in memory until the next one is
+// requested, so it can be sent again, or from any offset inside it. Previous offsets
+// return ErrUploadNotSeekable.
+func (u *Upload) chunk(offset, length int64) (io.ReadCloser, error) {
+	if rs, ok := u.stream.(io.ReadSeeker); ok {
+		return u.requestBody(newSection(rs, offset, length), offset, length), nil
+	}
+	if u.spool == nil {
+		u.spool = &spool{r: u.stream}
+	}
+	b, err := u.spool.read(offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return u.requestBody(bytes.NewReader(b), offset, length), nil
+}
+
+// contentType returns the MIME type sent in the X-Goog-Upload-Content-Type header.
+func (u *Upload) contentType() string {
+	if u.ContentType == "" {
+		return "application/octet-stream"
+	}
+	return u.ContentType
+}
+
+// requestBody returns rs, length bytes starting at offset in the upload, as a request body reporting progress.
+func (u *Upload) requestBody(rs io.ReadSeeker, offset, length int64) io.ReadCloser {
+	if u.progress == nil {
+		return requestBody{rs}
+	}
+	return requestBody{&progressBody{ReadSeeker: rs, reporter: u.progress, offset: offset, length: length}}
+}
+
+// requestBody is an io.ReadSeekCloser, so HTTP clients retrying requests, like the
+// one used by gphotos.NewClient, rewind it instead of loading it in memory.
+type requestBody struct {
+	io.ReadSeeker
+}
+
+func (requestBody) Close() error { return nil }
+
+// section is an io.ReadSeeker over length bytes of rs, starting at offset.
+type section struct {
+	rs             io.ReadSeeker
+	offset, length int64
+	pos            int64
+	seeked         bool
+}
+
+func newSection(rs io.ReadSeeker, offset, length int64) *section {
+	return &section{rs: rs, offset: offset, length: length}
+}
+
+func (s *section) Read(p []byte) (int, error) {
+	if !s.seeked {
+		if _, err := s.Seek(s.pos, io.SeekStart); err != nil {
+			return 0, err
+		}
+	}
+	if s.pos >= s.length {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.length-s.pos {
+		p = p[:s.length-s.pos]
+	}
+	n, err := s.rs.Read(p)
+	s.pos += int64(n)
+	return n, err
+}
+
+func (s *section) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += s.pos
+	case io.SeekEnd:
+		offset += s.length
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	if _, err := s.rs.Seek(s.offset+offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	s.pos = offset
+	s.seeked = true
+	return offset, nil
+}
+
+// onceReader is a non-seekable stream that can be rewound only before reading from it.
+type onceReader struct {
+	r    io.Reader
+	read bool
+}
+
+func (o *onceReader) Read(p []byte) (int, error) {
+	o.read = true
+	return o.r.Read(p)
+}
+
+func (o *onceReader) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart || o.read {
+		return 0, ErrUploadNotSeekable
+	}
+	return 0, nil
+}
+
+// spool reads a non-seekable stream forward, keeping the last chunk in memory.
+type spool struct {
+	r     io.Reader
+	buf   []byte
+	start int64 // offset of buf[0] in the stream.
+}
+
+// read returns length bytes starting at offset. The returned slice is valid until the next call.
+func (s *spool) read(offset, length int64) ([]byte, error) {
+	if offset < s.start {
+		return nil, ErrUploadNotSeekable
+	}
+	if end := s.start + int64(len(s.buf)); offset <= end {
+		n := copy(s.buf, s.buf[offset-s.start:])
+		s.buf = s.buf[:n]
+	} else {
+		if _, err := io.CopyN(io.Discard, s.r, offset-end); err != nil {
+			return nil, fmt.Errorf("reading upload: %w", err)
+		}
+		s.buf = s.buf[:0]
+	}
+	s.start = offset
+
+	if buffered := int64(len(s.buf)); buffered < length {
+		if int64(cap(s.buf)) < length {
+			buf := make([]byte, buffered, length)
+			copy(buf, s.buf)
+			s.buf = buf
+		}
+		n, err := io.ReadFull(s.r, s.buf[buffered:length])
+		s.buf = s.buf[:buffered+int64(n)]
+		if err != nil {
+			return nil, fmt.Errorf("reading upload: %w", err)
+		}
+	}
+	return s.buf[:length], nil
 }
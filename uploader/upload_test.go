@@ -2,9 +2,10 @@ package uploader_test
 
 import (
 	"bytes"
+	"errors"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+	"io"
 	"os"
-	"reflect"
 	"testing"
 )
 
@@ -14,11 +15,13 @@ func TestNewUploadFromFile(t *testing.T) {
 		path          string
 		wantName      string
 		wantSize      int64
+		wantType      string
 		isErrExpected bool
 	}{
-		{name: "sample JPEG 100kB", path: "testdata/file_example_JPG_100kB.jpg", wantName: "file_example_JPG_100kB.jpg", wantSize: 102117, isErrExpected: false},
-		{name: "sample PNG 500kB", path: "testdata/file_example_PNG_500kB.png", wantName: "file_example_PNG_500kB.png", wantSize: 512596, isErrExpected: false},
-		{name: "sample WEBP 50kB", path: "testdata/file_example_WEBP_50kB.webp", wantName: "file_example_WEBP_50kB.webp", wantSize: 50408, isErrExpected: false},
+		{name: "sample JPEG 100kB", path: "testdata/file_example_JPG_100kB.jpg", wantName: "file_example_JPG_100kB.jpg", wantSize: 102117, wantType: "image/jpeg", isErrExpected: false},
+		{name: "sample PNG 500kB", path: "testdata/file_example_PNG_500kB.png", wantName: "file_example_PNG_500kB.png", wantSize: 512596, wantType: "image/png", isErrExpected: false},
+		{name: "sample WEBP 50kB", path: "testdata/file_example_WEBP_50kB.webp", wantName: "file_example_WEBP_50kB.webp", wantSize: 50408, wantType: "image/webp", isErrExpected: false},
+		{name: "sample WEBP without extension", path: "testdata/upload-success", wantName: "upload-success", wantSize: 50408, wantType: "image/webp", isErrExpected: false},
 	}
 
 	for _, tc := range testCases {
@@ -27,9 +30,12 @@ func TestNewUploadFromFile(t *testing.T) {
 			if err != nil {
 				t.Fatalf("error was not expected at this point: %s", err)
 			}
-			_, err = uploader.NewUploadFromFile(f)
+			upload, err := uploader.NewUploadFromFile(f)
 			if err != nil && !tc.isErrExpected {
-				t.Errorf("error was not expected, err: %s", err)
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if err == nil && tc.wantType != upload.ContentType {
+				t.Errorf("want: %s, got: %s", tc.wantType, upload.ContentType)
 			}
 		})
 	}
@@ -42,7 +48,10 @@ func TestNewUpload(t *testing.T) {
 	wantName := "fooString"
 	wantFingerprint := "fooFingerprint"
 
-	upload := uploader.NewUpload(r, int64(reflect.TypeOf(input).Size()), wantName, wantFingerprint)
+	upload, err := uploader.NewUpload(r, int64(len(input)), wantName, wantFingerprint)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
 
 	if wantName != upload.Name {
 		t.Errorf("want: %s, got: %s", wantName, upload.Name)
@@ -51,4 +60,40 @@ func TestNewUpload(t *testing.T) {
 	if wantFingerprint != upload.Fingerprint {
 		t.Errorf("want: %s, got: %s", wantFingerprint, upload.Fingerprint)
 	}
+
+	if input != r.String() {
+		t.Errorf("want: reader not consumed, got: %q left", r.String())
+	}
+
+	t.Run("Should fail with nil reader", func(t *testing.T) {
+		if _, err := uploader.NewUpload(nil, 0, wantName, wantFingerprint); err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+
+	t.Run("Should fail with negative size", func(t *testing.T) {
+		if _, err := uploader.NewUpload(r, -1, wantName, wantFingerprint); err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+}
+
+// streamReader is a non-seekable stream of size bytes. It fails after failAt bytes, if set.
+type streamReader struct {
+	size, read, failAt int64
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	if s.failAt > 0 && s.read >= s.failAt {
+		return 0, errors.New("stream failed")
+	}
+	if s.read >= s.size {
+		return 0, io.EOF
+	}
+	n := int(min(int64(len(p)), s.size-s.read))
+	for i := range n {
+		p[i] = byte(s.read + int64(i))
+	}
+	s.read += int64(n)
+	return n, nil
 }
@@ -12,3 +12,18 @@ const defaultEndpoint = "https://photoslibrary.googleapis.com/v1/uploads"
 type HttpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
+
+// UploadOptions set the options of a single upload, for the UploadFileWithOptions and
+// UploadReaderWithOptions calls of the uploaders.
+type UploadOptions struct {
+	// OnProgress, if set, is called with the progress of the upload, instead of the uploader's OnProgress.
+	OnProgress ProgressFunc
+}
+
+// progressFunc returns the ProgressFunc of the options, or fn.
+func (o UploadOptions) progressFunc(fn ProgressFunc) ProgressFunc {
+	if o.OnProgress != nil {
+		return o.OnProgress
+	}
+	return fn
+}
@@ -29,6 +29,22 @@ type ResumableUploader struct {
 
 	// Store maps an upload's fingerprint with the corresponding upload URL.
 	Store Store
+
+	// Fingerprinter computes the fingerprints of the uploaded files. Defaults to
+	// MetadataFingerprinter. Use SHA256Fingerprinter, or the faster XXHashFingerprinter,
+	// to resume the uploads of files that have been touched or copied, at the cost of
+	// reading them once more.
+	Fingerprinter Fingerprinter
+
+	// ChunkSize is the size of the chunks sent in every request. It must be a
+	// multiple of 256 KiB. Defaults to DefaultChunkSize.
+	//
+	// Only the chunk being sent is kept in memory for non-seekable readers.
+	ChunkSize int64
+
+	// OnProgress, if set, is called with the progress of the uploads. See UploadOptions
+	// to report the progress of a single upload.
+	OnProgress ProgressFunc
 }
 
 // Store represents a service to map upload's fingerprint with
@@ -60,44 +76,94 @@ func NewResumableUploader(httpClient HttpClient) (*ResumableUploader, error) {
 // UploadFile returns the Google Photos upload token after uploading a file.
 // Any non-2xx status code is an error. Response headers are in error.(*googleapi.Error).Header.
 func (u *ResumableUploader) UploadFile(ctx context.Context, filePath string) (uploadToken string, err error) {
+	return u.UploadFileWithOptions(ctx, filePath, UploadOptions{})
+}
+
+// UploadFileWithOptions uploads a file like UploadFile, with the given options.
+func (u *ResumableUploader) UploadFileWithOptions(ctx context.Context, filePath string, options UploadOptions) (uploadToken string, err error) {
+	chunkSize, err := u.chunkSize()
+	if err != nil {
+		return "", fmt.Errorf("uploading file %s: %w", filePath, err)
+	}
 	f, err := os.Open(filePath)
 	if err != nil {
 		return "", fmt.Errorf("uploading file %s: %w", filePath, err)
 	}
 	defer utils.CloseOrLog(f, filePath)
 
-	upload, err := NewUploadFromFile(f)
+	fingerprinter := u.Fingerprinter
+	if fingerprinter == nil {
+		fingerprinter = MetadataFingerprinter{}
+	}
+	upload, err := NewUploadFromFileWithFingerprinter(f, fingerprinter)
 	if err != nil {
 		return "", fmt.Errorf("uploading file %s: %w", filePath, err)
 	}
 
 	u.Logger.Debugf("Starting resumable upload for file [%s].", filePath)
 
-	return u.createOrResumeUpload(ctx, upload)
+	return u.createOrResumeUpload(ctx, upload, chunkSize, options)
+}
+
+// UploadReader returns the Google Photos upload token after uploading the content of r,
+// of the given size, as a file with the given name.
+// Uploads from readers can't be resumed after the uploader is gone, because they have no fingerprint.
+// Any non-2xx status code is an error. Response headers are in error.(*googleapi.Error).Header.
+func (u *ResumableUploader) UploadReader(ctx context.Context, r io.Reader, size int64, name string) (uploadToken string, err error) {
+	return u.UploadReaderWithOptions(ctx, r, size, name, UploadOptions{})
+}
+
+// UploadReaderWithOptions uploads the content of r like UploadReader, with the given options.
+func (u *ResumableUploader) UploadReaderWithOptions(ctx context.Context, r io.Reader, size int64, name string, options UploadOptions) (uploadToken string, err error) {
+	chunkSize, err := u.chunkSize()
+	if err != nil {
+		return "", fmt.Errorf("uploading %s: %w", name, err)
+	}
+	upload, err := newUploadFromReader(r, size, name)
+	if err != nil {
+		return "", fmt.Errorf("uploading %s: %w", name, err)
+	}
+
+	u.Logger.Debugf("Starting resumable upload for [%s].", name)
+
+	return u.createOrResumeUpload(ctx, upload, chunkSize, options)
 }
 
-func (u *ResumableUploader) createOrResumeUpload(ctx context.Context, upload *Upload) (uploadToken string, err error) {
-	uploadToken, err = u.resumeUpload(ctx, upload)
+// createOrResumeUpload sends the upload in chunks of chunkSize bytes, which must have been validated.
+func (u *ResumableUploader) createOrResumeUpload(ctx context.Context, upload *Upload, chunkSize int64, options UploadOptions) (uploadToken string, err error) {
+	upload.progress = newProgressReporter(options.progressFunc(u.OnProgress), upload)
 
-	if err == nil {
-		return uploadToken, nil
+	if url, found := u.storedUploadURL(upload); found {
+		uploadToken, err = u.resumeUpload(ctx, upload, url, chunkSize)
+		if err == nil {
+			return uploadToken, nil
+		}
+		u.Logger.Debugf("Failed to resume upload for [%s], starting a new one: %s", upload.Name, err)
 	}
 
-	uploadToken, err = u.createUpload(ctx, upload)
+	uploadToken, err = u.createUpload(ctx, upload, chunkSize)
 	if err != nil {
 		return "", fmt.Errorf("resuming upload: %w", err)
 	}
 	return uploadToken, nil
 }
 
-func (u *ResumableUploader) createUpload(ctx context.Context, upload *Upload) (uploadToken string, err error) {
+// storedUploadURL returns the upload URL of a previous upload with the same fingerprint, if any.
+func (u *ResumableUploader) storedUploadURL(upload *Upload) (string, bool) {
+	if !u.isResumeEnabled() || len(upload.Fingerprint) == 0 {
+		return "", false
+	}
+	return u.Store.Get(upload.Fingerprint)
+}
+
+func (u *ResumableUploader) createUpload(ctx context.Context, upload *Upload, chunkSize int64) (uploadToken string, err error) {
 	req, err := http.NewRequest("POST", u.BaseURL, nil)
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Length", "0")
 	req.Header.Set("X-Goog-Upload-Command", "start")
-	req.Header.Set("X-Goog-Upload-Content-Type", "application/octet-stream")
+	req.Header.Set("X-Goog-Upload-Content-Type", upload.contentType())
 	req.Header.Set("X-Goog-Upload-File-Name", upload.Name)
 	req.Header.Set("X-Goog-Upload-Protocol", "resumable")
 	req.Header.Set("X-Goog-Upload-Raw-Size", strconv.FormatInt(upload.size, 10))
@@ -108,66 +174,123 @@ func (u *ResumableUploader) createUpload(ctx context.Context, upload *Upload) (u
 	}
 	defer utils.CloseOrLog(res.Body, "resumable upload response body - createUpload")
 
-	if http.StatusOK == res.StatusCode {
-		location := res.Header.Get("X-Goog-Upload-URL")
+	location := res.Header.Get("X-Goog-Upload-URL")
+	if location == "" {
+		return "", ErrUploadURLMissing
+	}
 
-		if u.isResumeEnabled() {
-			u.Store.Set(upload.Fingerprint, location)
-		}
+	if u.isResumeEnabled() && len(upload.Fingerprint) > 0 {
+		u.Store.Set(upload.Fingerprint, location)
 	}
 
-	return u.resumeUpload(ctx, upload)
+	return u.resumeUpload(ctx, upload, location, chunkSize)
 }
 
 func (u *ResumableUploader) isResumeEnabled() bool {
 	return u.Store != nil
 }
 
-func (u *ResumableUploader) resumeUpload(ctx context.Context, upload *Upload) (uploadToken string, err error) {
-	if len(upload.Fingerprint) == 0 {
-		return "", ErrFingerprintNotSet
+func (u *ResumableUploader) resumeUpload(ctx context.Context, upload *Upload, url string, chunkSize int64) (uploadToken string, err error) {
+	offset, err := u.getUploadOffset(ctx, url)
+	if err != nil {
+		return "", err
 	}
-	url, found := u.Store.Get(upload.Fingerprint)
+	upload.progress.resumed(offset)
+
+	failures := 0
+	for {
+		length := min(chunkSize, upload.size-offset)
+		last := offset+length == upload.size
 
-	if !found {
-		return "", ErrUploadNotFound
+		uploadToken, err = u.uploadChunk(ctx, upload, url, offset, length, last)
+		if err == nil && last {
+			break
+		}
+		if err == nil {
+			offset += length
+			failures = 0
+			continue
+		}
+
+		u.Logger.Errorf("Failed to upload chunk at offset %d: %s", offset, err)
+		failures++
+		if ctx.Err() != nil || failures == maxChunkAttempts {
+			return "", fmt.Errorf("resuming upload: %w", err)
+		}
+
+		// The server could have received part of the chunk. Continue from the bytes it has.
+		if offset, err = u.getUploadOffset(ctx, url); err != nil {
+			return "", fmt.Errorf("resuming upload: %w", err)
+		}
+		u.Logger.Debugf("Resuming upload at offset %d.", offset)
 	}
 
-	offset, err := u.getUploadOffset(ctx, url)
-	if err != nil {
-		return "", err
+	if u.isResumeEnabled() && len(upload.Fingerprint) > 0 {
+		u.Store.Delete(upload.Fingerprint)
 	}
+	upload.progress.finish()
 
-	if _, err := upload.stream.Seek(offset, io.SeekStart); err != nil {
-		return "", err
+	return uploadToken, nil
+}
+
+const (
+	// DefaultChunkSize is the size of the chunks sent by resumable uploads when none is configured.
+	DefaultChunkSize = 8 << 20
+
+	// chunkGranularity is the size that chunks must be a multiple of, except the last one.
+	//
+	// See: https://developers.google.com/photos/library/guides/resumable-uploads#uploading-file-chunks.
+	chunkGranularity = 256 << 10
+
+	// maxChunkAttempts is the number of consecutive times a chunk is sent before failing the upload.
+	maxChunkAttempts = 3
+)
+
+// chunkSize returns the configured chunk size, or DefaultChunkSize.
+func (u *ResumableUploader) chunkSize() (int64, error) {
+	if u.ChunkSize == 0 {
+		return DefaultChunkSize, nil
 	}
+	if u.ChunkSize < 0 || u.ChunkSize%chunkGranularity != 0 {
+		return 0, fmt.Errorf("chunk size must be a positive multiple of %d bytes", chunkGranularity)
+	}
+	return u.ChunkSize, nil
+}
 
-	req, err := http.NewRequest("POST", url, upload.stream)
+// uploadChunk sends length bytes of the upload starting at offset. The last chunk finalizes
+// the upload, returning the upload token.
+func (u *ResumableUploader) uploadChunk(ctx context.Context, upload *Upload, url string, offset, length int64, last bool) (uploadToken string, err error) {
+	body, err := upload.chunk(offset, length)
 	if err != nil {
 		return "", err
 	}
-
-	req.Header.Set("Content-Length", strconv.FormatInt(upload.size, 10))
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Length", strconv.FormatInt(length, 10))
 	req.Header.Set("X-Goog-Upload-Offset", strconv.FormatInt(offset, 10))
-	req.Header.Set("X-Goog-Upload-Command", "upload, finalize")
+	req.Header.Set("X-Goog-Upload-Command", "upload")
+	if last {
+		req.Header.Set("X-Goog-Upload-Command", "upload, finalize")
+	}
 
 	res, err := u.doRequest(ctx, req)
 	if err != nil {
-		u.Logger.Errorf("Failed to resume upload: %s", err)
-		return "", fmt.Errorf("resuming upload: %w", err)
+		return "", err
+	}
+	defer utils.CloseOrLog(res.Body, "resumable upload response body - uploadChunk")
+
+	if !last {
+		return "", nil
 	}
-	defer utils.CloseOrLog(res.Body, "resumable upload response body - resumeUpload")
 
 	b, err := io.ReadAll(res.Body)
 	if err != nil {
 		u.Logger.Errorf("Failed to read response: %s", err)
-		return "", fmt.Errorf("resuming upload: %w", err)
-	}
-
-	if u.isResumeEnabled() {
-		u.Store.Delete(upload.Fingerprint)
+		return "", err
 	}
-
 	return string(b), nil
 }
 
@@ -1,10 +1,15 @@
 package uploader_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -62,6 +67,182 @@ func TestResumableUploader_UploadFile(t *testing.T) {
 	}
 }
 
+func TestResumableUploader_UploadReader(t *testing.T) {
+	// Starts like a JPEG file.
+	content := "\xFF\xD8\xFF" + strings.Repeat("foo bar baz ", 100)
+	testCases := []struct {
+		name        string
+		reader      io.Reader
+		store       uploader.Store
+		fileName    string
+		errExpected bool
+	}{
+		{"Should be successful with a seekable reader", strings.NewReader(content), NewMockStore(), "foo.jpg", false},
+		{"Should be successful with a non-seekable reader", bytes.NewBufferString(content), NewMockStore(), "foo.jpg", false},
+		{"Should be successful without store", strings.NewReader(content), nil, "foo.jpg", false},
+		{"Should fail when the upload fails", strings.NewReader(content), nil, mocks.UploadShouldFail, true},
+		{"Should fail without name", strings.NewReader(content), nil, "", true},
+	}
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := uploader.NewResumableUploader(http.DefaultClient)
+			if err != nil {
+				t.Fatalf("error was not expected at this point, err: %s", err)
+			}
+			u.BaseURL = srv.URL() + "/v1/uploads"
+			u.Store = tc.store
+
+			got, err := u.UploadReader(context.Background(), tc.reader, int64(len(content)), tc.fileName)
+			if tc.errExpected && err == nil {
+				t.Fatalf("error was expected, but not produced")
+			}
+			if !tc.errExpected && err != nil {
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if err == nil && mocks.UploadToken != got {
+				t.Errorf("want: %s, got: %s", mocks.UploadToken, got)
+			}
+		})
+	}
+}
+
+func TestResumableUploader_UploadURLMissing(t *testing.T) {
+	// Starts upload sessions without sending their URL.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	u, err := uploader.NewResumableUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point, err: %s", err)
+	}
+	u.BaseURL = srv.URL
+
+	content := "\xFF\xD8\xFF foo bar baz"
+	_, err = u.UploadReader(context.Background(), strings.NewReader(content), int64(len(content)), "foo.jpg")
+	if !errors.Is(err, uploader.ErrUploadURLMissing) {
+		t.Errorf("want: %v, got: %v", uploader.ErrUploadURLMissing, err)
+	}
+}
+
+func TestResumableUploader_UploadReader_Stream(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	u, err := uploader.NewResumableUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point, err: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+
+	// Bigger than two chunks.
+	const size = 20 << 20
+
+	t.Run("Should upload a non-seekable stream in chunks", func(t *testing.T) {
+		got, err := u.UploadReader(context.Background(), &streamReader{size: size}, size, "foo.mp4")
+		if err != nil {
+			t.Fatalf("error was not expected, err: %s", err)
+		}
+		if mocks.UploadToken != got {
+			t.Errorf("want: %s, got: %s", mocks.UploadToken, got)
+		}
+	})
+
+	t.Run("Should fail when the stream fails", func(t *testing.T) {
+		_, err := u.UploadReader(context.Background(), &streamReader{size: size, failAt: size / 2}, size, "foo.mp4")
+		if err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+
+	t.Run("Should fail when the stream is shorter than its size", func(t *testing.T) {
+		_, err := u.UploadReader(context.Background(), &streamReader{size: size - 1}, size, "foo.mp4")
+		if err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+	})
+}
+
+func TestResumableUploader_ChunkSize(t *testing.T) {
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	// Four full chunks and a partial one.
+	const size = 4*256<<10 + 100
+	content := bytes.Repeat([]byte("foo bar baz "), size/12+1)[:size]
+
+	testCases := []struct {
+		name        string
+		reader      func() io.Reader
+		fileName    string
+		chunkSize   int64
+		errExpected bool
+	}{
+		{"Should upload a seekable reader in chunks", func() io.Reader { return bytes.NewReader(content) }, "foo.mp4", 256 << 10, false},
+		{"Should upload a non-seekable reader in chunks", func() io.Reader { return &streamReader{size: size} }, "foo.mp4", 256 << 10, false},
+		{"Should upload a reader in one chunk", func() io.Reader { return bytes.NewReader(content) }, "foo.mp4", 2 << 20, false},
+		{"Should resume a seekable reader after a failed chunk", func() io.Reader { return bytes.NewReader(content) }, mocks.UploadShouldFailChunk, 256 << 10, false},
+		{"Should resume a non-seekable reader after a failed chunk", func() io.Reader { return &streamReader{size: size} }, mocks.UploadShouldFailChunk, 256 << 10, false},
+		{"Should fail with a chunk size not multiple of 256 KiB", func() io.Reader { return bytes.NewReader(content) }, "foo.mp4", 1000, true},
+		{"Should fail with a negative chunk size", func() io.Reader { return bytes.NewReader(content) }, "foo.mp4", -256 << 10, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := uploader.NewResumableUploader(http.DefaultClient)
+			if err != nil {
+				t.Fatalf("error was not expected at this point, err: %s", err)
+			}
+			u.BaseURL = srv.URL() + "/v1/uploads"
+			u.ChunkSize = tc.chunkSize
+
+			got, err := u.UploadReader(context.Background(), tc.reader(), size, tc.fileName)
+			if tc.errExpected && err == nil {
+				t.Fatalf("error was expected, but not produced")
+			}
+			if !tc.errExpected && err != nil {
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if err == nil && mocks.UploadToken != got {
+				t.Errorf("want: %s, got: %s", mocks.UploadToken, got)
+			}
+		})
+	}
+}
+
+func TestResumableUploader_InvalidChunkSize(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { requests++ }))
+	defer srv.Close()
+
+	u, err := uploader.NewResumableUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point, err: %s", err)
+	}
+	u.BaseURL = srv.URL
+	u.ChunkSize = 1000
+
+	t.Run("Should fail before reading the file", func(t *testing.T) {
+		// The file doesn't exist, so the error would be different if it were opened.
+		_, err := u.UploadFile(context.Background(), "testdata/non-existent.jpg")
+		if err == nil || !strings.Contains(err.Error(), "chunk size") {
+			t.Errorf("want: chunk size error, got: %v", err)
+		}
+	})
+
+	t.Run("Should fail before starting the upload", func(t *testing.T) {
+		content := "\xFF\xD8\xFF foo bar baz"
+		if _, err := u.UploadReader(context.Background(), strings.NewReader(content), int64(len(content)), "foo.jpg"); err == nil {
+			t.Errorf("error was expected, but not produced")
+		}
+		if requests != 0 {
+			t.Errorf("want: no requests, got: %d", requests)
+		}
+	})
+}
+
 func TestResumableUploader_IsResumeEnabled(t *testing.T) {
 	t.Run("Should return False by default", func(t *testing.T) {
 		u, err := uploader.NewResumableUploader(http.DefaultClient)
@@ -23,6 +23,10 @@ type SimpleUploader struct {
 
 	// Logger used to log messages.
 	Logger log.Logger
+
+	// OnProgress, if set, is called with the progress of the uploads. See UploadOptions
+	// to report the progress of a single upload.
+	OnProgress ProgressFunc
 }
 
 // NewSimpleUploader returns a new client to upload files to Google Photos.
@@ -45,27 +49,52 @@ func NewSimpleUploader(httpClient HttpClient) (*SimpleUploader, error) {
 // A successful upload request returns an upload token. Use this upload
 // token to create a media item with [media_items.Create].
 func (u *SimpleUploader) UploadFile(ctx context.Context, filePath string) (uploadToken string, err error) {
+	return u.UploadFileWithOptions(ctx, filePath, UploadOptions{})
+}
+
+// UploadFileWithOptions uploads a file like UploadFile, with the given options.
+func (u *SimpleUploader) UploadFileWithOptions(ctx context.Context, filePath string, options UploadOptions) (uploadToken string, err error) {
 	f, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
+	defer utils.CloseOrLog(f, filePath)
 
 	upload, err := NewUploadFromFile(f)
 	if err != nil {
 		return "", err
 	}
 
-	return u.upload(ctx, upload)
+	return u.upload(ctx, upload, options)
+}
+
+// UploadReader uploads the content of r, of the given size, to Google Photos as
+// a file with the given name. It returns an upload token like UploadFile.
+func (u *SimpleUploader) UploadReader(ctx context.Context, r io.Reader, size int64, name string) (uploadToken string, err error) {
+	return u.UploadReaderWithOptions(ctx, r, size, name, UploadOptions{})
+}
+
+// UploadReaderWithOptions uploads the content of r like UploadReader, with the given options.
+func (u *SimpleUploader) UploadReaderWithOptions(ctx context.Context, r io.Reader, size int64, name string, options UploadOptions) (uploadToken string, err error) {
+	upload, err := newUploadFromReader(r, size, name)
+	if err != nil {
+		return "", err
+	}
+
+	return u.upload(ctx, upload, options)
 }
 
-func (u *SimpleUploader) upload(ctx context.Context, upload *Upload) (uploadToken string, err error) {
-	req, err := http.NewRequest("POST", u.BaseURL, upload.stream)
+func (u *SimpleUploader) upload(ctx context.Context, upload *Upload, options UploadOptions) (uploadToken string, err error) {
+	upload.progress = newProgressReporter(options.progressFunc(u.OnProgress), upload)
+
+	req, err := http.NewRequest("POST", u.BaseURL, upload.body())
 	if err != nil {
 		return "", err
 	}
+	req.ContentLength = upload.size
 	req.Header.Set("Content-Length", strconv.FormatInt(upload.size, 10))
 	req.Header.Set("Content-Type", "application/octet-stream")
-	req.Header.Set("X-Goog-Upload-Content-Type", "application/octet-stream")
+	req.Header.Set("X-Goog-Upload-Content-Type", upload.contentType())
 	req.Header.Set("X-Goog-Upload-File-Name", upload.Name)
 	req.Header.Set("X-Goog-Upload-Protocol", "raw")
 
@@ -83,6 +112,7 @@ func (u *SimpleUploader) upload(ctx context.Context, upload *Upload) (uploadToke
 		u.Logger.Errorf("Error while uploading %s: %s: could not read body: %s", upload, res.Status, err)
 		return "", err
 	}
+	upload.progress.finish()
 
 	return string(body), nil
 
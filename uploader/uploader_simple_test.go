@@ -1,10 +1,13 @@
 package uploader_test
 
 import (
+	"bytes"
 	"context"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/mocks"
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -16,7 +19,7 @@ func TestNewSimpleUploader(t *testing.T) {
 	want := "https://photoslibrary.googleapis.com/v1/uploads"
 
 	if want != got.BaseURL {
-		t.Errorf("want: %s, got: %s", want, got)
+		t.Errorf("want: %s, got: %s", want, got.BaseURL)
 	}
 }
 
@@ -55,3 +58,47 @@ func TestSimpleUploader_UploadFile(t *testing.T) {
 		})
 	}
 }
+
+func TestSimpleUploader_UploadReader(t *testing.T) {
+	// Starts like a JPEG file.
+	content := "\xFF\xD8\xFF foo bar baz"
+	testCases := []struct {
+		name        string
+		reader      io.Reader
+		size        int64
+		fileName    string
+		errExpected bool
+	}{
+		{name: "Should be successful with a seekable reader", reader: strings.NewReader(content), size: int64(len(content)), fileName: "foo.jpg"},
+		{name: "Should be successful with a non-seekable reader", reader: bytes.NewBufferString(content), size: int64(len(content)), fileName: "foo.jpg"},
+		{name: "Should be successful with a stream", reader: &streamReader{size: 1 << 20}, size: 1 << 20, fileName: "foo.mp4"},
+		{name: "Should fail when the stream fails", reader: &streamReader{size: 1 << 20, failAt: 1 << 19}, size: 1 << 20, fileName: "foo.mp4", errExpected: true},
+		{name: "Should fail when the upload fails", reader: strings.NewReader(content), size: int64(len(content)), fileName: mocks.UploadShouldFail, errExpected: true},
+		{name: "Should fail with a wrong size", reader: strings.NewReader(content), size: 1, fileName: "foo.jpg", errExpected: true},
+		{name: "Should fail with a negative size", reader: strings.NewReader(content), size: -1, fileName: "foo.jpg", errExpected: true},
+		{name: "Should fail without name", reader: strings.NewReader(content), size: int64(len(content)), fileName: "", errExpected: true},
+	}
+	srv := mocks.NewMockedGooglePhotosService()
+	defer srv.Close()
+
+	u, err := uploader.NewSimpleUploader(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error was not expected at this point: %s", err)
+	}
+	u.BaseURL = srv.URL() + "/v1/uploads"
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := u.UploadReader(context.Background(), tc.reader, tc.size, tc.fileName)
+			if tc.errExpected && err == nil {
+				t.Fatalf("error was expected, but not produced")
+			}
+			if !tc.errExpected && err != nil {
+				t.Fatalf("error was not expected, err: %s", err)
+			}
+			if err == nil && mocks.UploadToken != got {
+				t.Errorf("want: %s, got: %s", mocks.UploadToken, got)
+			}
+		})
+	}
+}